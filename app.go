@@ -0,0 +1,323 @@
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pulsar-go/pulsar/config"
+	"github.com/pulsar-go/pulsar/db"
+	"github.com/pulsar-go/pulsar/mail"
+	"github.com/pulsar-go/pulsar/queue"
+	"github.com/pulsar-go/pulsar/router"
+	"github.com/rs/cors"
+)
+
+// defaultShutdownTimeout is the time given to the server to shut down
+// when no shutdown timeout is configured.
+const defaultShutdownTimeout = 10 * time.Second
+
+// watchInterval is how often the watched configuration directory is checked.
+const watchInterval = 2 * time.Second
+
+// App represents a pulsar application that owns its
+// configuration, routes, database, queue and mailer.
+type App struct {
+	Config *config.Config
+	Router *router.Router
+	DB     *db.DB
+	Models []interface{}
+	Queue  *queue.Queue
+	Mailer *mail.Mailer
+	// settings are the settings read while serving, published
+	// from Config when serving starts and replaced on reloads.
+	settings *config.Snapshot
+	// watchDir is the configuration directory reloaded while serving.
+	watchDir string
+	// global determines if the application uses and
+	// publishes the package level globals.
+	global        bool
+	hooksMutex    sync.Mutex
+	startHooks    []Hook
+	shutdownHooks []Hook
+}
+
+// defaultApp is the application wired to the package level globals.
+var defaultApp = &App{
+	Config:   &config.Settings,
+	Router:   &router.Routes,
+	Mailer:   mail.Default,
+	settings: config.Global(),
+	global:   true,
+}
+
+// New creates a new application with the given configuration.
+func New(c *config.Config) *App {
+	settings := config.NewSnapshot(c)
+	return &App{
+		Config:   c,
+		Router:   &router.Router{},
+		Mailer:   &mail.Mailer{Settings: settings},
+		settings: settings,
+	}
+}
+
+// Default returns the application that uses config.Settings, router.Routes,
+// db.Builder, db.Models, queue.Default and mail.Default.
+func Default() *App {
+	return defaultApp
+}
+
+// AddModels add the given models to the application model list.
+func (app *App) AddModels(models ...interface{}) *App {
+	app.Models = append(app.Models, models...)
+	return app
+}
+
+// models returns the models that need to be migrated.
+func (app *App) models() []interface{} {
+	if app.global {
+		return append(append([]interface{}{}, db.Models...), app.Models...)
+	}
+	return app.Models
+}
+
+// Watch reloads the configuration from the given directory
+// while the application server is running.
+func (app *App) Watch(dir string) *App {
+	app.watchDir = dir
+	return app
+}
+
+// Handler returns the http handler of the application routes.
+func (app *App) Handler() http.Handler {
+	return app.cors(app.mux())
+}
+
+// mux creates a handler that dispatches to the application routes.
+func (app *App) mux() http.Handler {
+	// Register the application routes.
+	return newHostRouter(httprouter.New(), app.Router, app.Config, app.current).handler()
+}
+
+// cors wraps the handler with the current CORS settings of the application.
+func (app *App) cors(handler http.Handler) http.Handler {
+	s := &app.current().Server
+	return cors.New(cors.Options{
+		AllowedOrigins:     s.AllowedOrigins,
+		AllowedHeaders:     s.AllowedHeaders,
+		AllowedMethods:     s.AllowedMethods,
+		AllowCredentials:   s.AllowCredentials,
+		ExposedHeaders:     s.ExposedHeaders,
+		Debug:              s.Development,
+		OptionsPassthrough: true,
+	}).Handler(handler)
+}
+
+// current returns the settings read while serving, or
+// the application configuration before serving.
+func (app *App) current() *config.Config {
+	if app.settings != nil {
+		if c := app.settings.Load(); c != nil {
+			return c
+		}
+	}
+	return app.Config
+}
+
+// reload applies the reloaded settings to the running server.
+func (app *App) reload(handler *swapHandler, mux http.Handler) config.Subscriber {
+	return func(previous, current config.Config) {
+		handler.Store(app.cors(mux))
+		if previous.Queue.Routines != current.Queue.Routines {
+			routines, _ := strconv.Atoi(current.Queue.Routines)
+			app.Queue.Resize(routines)
+		}
+		if app.Config.Server.Development {
+			fmt.Println("Configuration reloaded")
+		}
+	}
+}
+
+// swapHandler is a http handler that can be replaced while serving.
+type swapHandler struct {
+	handler atomic.Value
+}
+
+// Store replaces the handler.
+func (h *swapHandler) Store(handler http.Handler) {
+	h.handler.Store(handler)
+}
+
+// ServeHTTP serves the request using the current handler.
+func (h *swapHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.Load().(http.Handler).ServeHTTP(w, r)
+}
+
+// open creates the queue of the application and opens the
+// database connection when a database driver is configured.
+func (app *App) open() error {
+	// Configure the queue system.
+	routines, err := strconv.ParseInt(app.Config.Queue.Routines, 10, 32)
+	if err != nil {
+		return err
+	}
+	q, err := queue.New(int(routines))
+	if err != nil {
+		return err
+	}
+	// Set the database configuration, which is optional.
+	if app.Config.Database.Driver != "" {
+		database, err := db.Connect(&app.Config.Database)
+		if err != nil {
+			q.Release()
+			return err
+		}
+		app.DB = database
+		if !app.global && app.Router.Database == nil {
+			app.Router.Database = database
+		}
+		// Migrate if nessesary
+		if app.Config.Database.AutoMigrate {
+			app.DB.AutoMigrate(app.models()...)
+		}
+	}
+	app.Queue = q
+	if app.Mailer != nil && app.Mailer.Queue == nil && !app.global {
+		app.Mailer.Queue = q
+	}
+	if app.global {
+		db.Builder = app.DB
+		queue.Default = app.Queue
+		queue.Pool = app.Queue.Pool
+	}
+	return nil
+}
+
+// close releases the queue and closes the database connection.
+func (app *App) close() {
+	app.Queue.Release()
+	if app.DB != nil {
+		app.DB.Close()
+	}
+}
+
+// Serve starts the application server and blocks until it fails or
+// until a SIGINT or SIGTERM signal gracefully shuts it down.
+func (app *App) Serve() error {
+	if err := app.Config.Validate(); err != nil {
+		return err
+	}
+	// Publish the settings read while serving.
+	if app.settings == nil {
+		app.settings = &config.Snapshot{}
+	}
+	app.settings.Store(app.Config)
+	mux := app.mux()
+	handler := &swapHandler{}
+	handler.Store(app.cors(mux))
+	// Set the address of the server.
+	address := app.Config.Server.Host + ":" + app.Config.Server.Port
+	// Generate SSL.
+	generateSSLCertificate(&app.Config.Certificate, address)
+	if err := app.open(); err != nil {
+		return err
+	}
+	defer app.close()
+	if app.watchDir != "" {
+		watcher := config.NewWatcher(app.watchDir, app.settings).Subscribe(app.reload(handler, mux))
+		watcher.Start(watchInterval)
+		defer watcher.Stop()
+	}
+	if app.Config.Server.Development {
+		fmt.Println("-----------------------------------------------------")
+		fmt.Println("|                                                   |")
+		fmt.Println("|  P U L S A R                                      |")
+		fmt.Println("|  Go Web framework                                 |")
+		fmt.Println("|                                                   |")
+		fmt.Println("|  Erik Campobadal <soc@erik.cat>                   |")
+		fmt.Println("|  Krishan König <krishan.koenig@googlemail.com>    |")
+		fmt.Println("|                                                   |")
+		fmt.Println("-----------------------------------------------------")
+		fmt.Println()
+		app.Router.Print(os.Stdout)
+		fmt.Println()
+	}
+	// Listen to the termination signals before starting, so the ones
+	// received while the start hooks run also shut down gracefully.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	// Bind the address before running the start hooks, so they only
+	// run when the server starts and the shutdown hooks always follow.
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Addr: address, Handler: handler}
+	serverErrors := make(chan error, 1)
+	go func() {
+		if app.Config.Certificate.Enabled {
+			if app.Config.Server.Development {
+				fmt.Printf("Creating a HTTP/2 server with TLS on %s\n", address)
+				fmt.Printf("Certificate: %s\nKey: %s\n\n", app.Config.Certificate.CertFile, app.Config.Certificate.KeyFile)
+			}
+			serverErrors <- server.ServeTLS(listener, app.Config.Certificate.CertFile, app.Config.Certificate.KeyFile)
+			return
+		}
+		if app.Config.Server.Development {
+			fmt.Printf("Creating a HTTP/1.1 server on %s\n\n", address)
+		}
+		serverErrors <- server.Serve(listener)
+	}()
+	app.runHooks("start", &app.startHooks)
+	// Wait for the server to fail or for a termination signal.
+	select {
+	case err := <-serverErrors:
+		log.Printf("[PULSAR] The server failed: %s\n", err)
+		app.shutdown(server)
+		return err
+	case sig := <-signals:
+		if app.Config.Server.Development {
+			fmt.Printf("\nReceived %s, shutting down the server...\n", sig)
+		}
+	}
+	return app.shutdown(server)
+}
+
+// shutdown gracefully stops the server, draining the active connections and
+// the queued jobs within the configured shutdown timeout.
+func (app *App) shutdown(server *http.Server) error {
+	timeout := defaultShutdownTimeout
+	if app.Config.Server.ShutdownTimeout != "" {
+		t, err := time.ParseDuration(app.Config.Server.ShutdownTimeout)
+		if err != nil {
+			log.Printf("[PULSAR] Invalid shutdown timeout %s, using %s\n", app.Config.Server.ShutdownTimeout, timeout)
+		} else {
+			timeout = t
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	// Stop accepting new connections and wait for the active ones.
+	err := server.Shutdown(ctx)
+	if err != nil {
+		log.Printf("[PULSAR] Failed to drain the active connections: %s\n", err)
+	}
+	// Wait for the queued jobs to finish.
+	if qerr := app.Queue.Wait(ctx); qerr != nil {
+		log.Printf("[PULSAR] Failed to wait for the queued jobs: %s\n", qerr)
+	}
+	app.runHooks("shutdown", &app.shutdownHooks)
+	return err
+}
@@ -0,0 +1,288 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pulsar-go/pulsar/cookie"
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+	"github.com/pulsar-go/pulsar/router"
+	"github.com/pulsar-go/pulsar/session"
+)
+
+// ErrorNoGuard is returned when the guard middleware didn't run.
+var ErrorNoGuard = errors.New("The request has no guard, use the guard middleware")
+
+// key stores the authentication state in the request context.
+var key = request.NewKey("auth")
+
+// state is the authentication state of a request.
+type state struct {
+	guard *Guard
+	user  interface{}
+	// cookie is the remember cookie set in the response, or
+	// removed when forget is true.
+	cookie *http.Cookie
+	forget bool
+}
+
+// Guard authenticates the users of the routes using the session, so the
+// session middleware must run before the guard middleware. Groups of routes
+// can use different guards, like one for the users and another for the admins.
+type Guard struct {
+	// Name identifies the guard in the session and its remember cookie.
+	Name     string
+	Provider *Provider
+	// Hasher checks the passwords, they're checked with Verify when nil.
+	Hasher Hasher
+	// RememberLifetime is how long the remember cookie lasts.
+	RememberLifetime time.Duration
+	// LoginURL is where the guests are redirected by RequireAuth,
+	// they get a 401 response when it's empty or they expect JSON.
+	LoginURL string
+}
+
+// Default is the guard used by the package middlewares and functions.
+var Default *Guard
+
+// NewGuard creates a guard of the provider users, with a remember
+// cookie lasting 30 days.
+func NewGuard(name string, provider *Provider) *Guard {
+	return &Guard{Name: name, Provider: provider, RememberLifetime: 30 * 24 * time.Hour}
+}
+
+// sessionKey returns the session key storing the user id.
+func (g *Guard) sessionKey() string {
+	return "auth_" + g.Name
+}
+
+// rememberCookie returns the name of the remember cookie.
+func (g *Guard) rememberCookie() string {
+	return "pulsar_remember_" + g.Name
+}
+
+// Middleware loads the user of the request, from the session or the
+// remember cookie, so it's available with Auth.
+func (g *Guard) Middleware(next router.Handler) router.Handler {
+	return func(req *request.HTTP) response.HTTP {
+		st := &state{guard: g, user: g.load(req)}
+		req.Set(key, st)
+		res := next(req)
+		if st.forget {
+			return res.WithoutCookie(g.rememberCookie())
+		}
+		if st.cookie != nil {
+			return res.WithEncryptedCookie(st.cookie)
+		}
+		return res
+	}
+}
+
+// RequireAuth loads the user of the request and rejects the guests.
+func (g *Guard) RequireAuth(next router.Handler) router.Handler {
+	return g.Middleware(func(req *request.HTTP) response.HTTP {
+		if !Check(req) {
+			return g.unauthorized(req)
+		}
+		return next(req)
+	})
+}
+
+// unauthorized responds to a guest.
+func (g *Guard) unauthorized(req *request.HTTP) response.HTTP {
+	if g.LoginURL == "" || req.WantsJSON() {
+		return response.JSONWithCode(map[string]string{"error": http.StatusText(http.StatusUnauthorized)}, http.StatusUnauthorized)
+	}
+	return response.Redirect(g.LoginURL)
+}
+
+// load returns the user of the session or the remember cookie, or nil for guests.
+func (g *Guard) load(req *request.HTTP) interface{} {
+	s, err := session.Of(req)
+	if err != nil {
+		log.Printf("[PULSAR] Unable to authenticate: %s\n", err)
+		return nil
+	}
+	if id, ok := s.GetInt(g.sessionKey()); ok {
+		user, err := g.Provider.ByID(uint(id))
+		if err != nil {
+			log.Printf("[PULSAR] Unable to load the user: %s\n", err)
+		}
+		if user != nil {
+			return user
+		}
+	}
+	value, err := req.EncryptedCookie(g.rememberCookie())
+	if err != nil {
+		return nil
+	}
+	parts := strings.SplitN(value, "|", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil
+	}
+	user, err := g.Provider.ByRememberToken(uint(id), parts[1])
+	if user == nil || err != nil {
+		if err != nil {
+			log.Printf("[PULSAR] Unable to load the user: %s\n", err)
+		}
+		return nil
+	}
+	// The user is logged in again, since the session expired.
+	if err := s.Regenerate(); err != nil {
+		log.Printf("[PULSAR] Unable to regenerate the session: %s\n", err)
+	}
+	s.Put(g.sessionKey(), g.Provider.ID(user))
+	return user
+}
+
+// current returns the authentication state of the guard.
+func (g *Guard) current(req *request.HTTP) (*state, *session.Session, error) {
+	value, ok := req.Get(key)
+	if !ok || value.(*state).guard != g {
+		return nil, nil, ErrorNoGuard
+	}
+	s, err := session.Of(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return value.(*state), s, nil
+}
+
+// Attempt logs in the user with the identifier, like the email, and the
+// password. It returns false when the credentials don't match.
+func (g *Guard) Attempt(req *request.HTTP, identifier, password string, remember bool) (bool, error) {
+	user, err := g.Provider.ByIdentifier(identifier)
+	if user == nil || err != nil {
+		return false, err
+	}
+	hash := g.Provider.PasswordHash(user)
+	if g.Hasher != nil && !g.Hasher.Check(hash, password) || g.Hasher == nil && !Verify(hash, password) {
+		return false, nil
+	}
+	return true, g.Login(req, user, remember)
+}
+
+// Login logs in the user, regenerating the session. When remember is
+// true, the user stays logged in after the session expires.
+func (g *Guard) Login(req *request.HTTP, user interface{}, remember bool) error {
+	st, s, err := g.current(req)
+	if err != nil {
+		return err
+	}
+	if err := s.Regenerate(); err != nil {
+		return err
+	}
+	s.Put(g.sessionKey(), g.Provider.ID(user))
+	st.user = user
+	if !remember {
+		return nil
+	}
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(random)
+	if err := g.Provider.SetRememberToken(user, token); err != nil {
+		return err
+	}
+	st.cookie = cookie.New(g.rememberCookie(), fmt.Sprintf("%d|%s", g.Provider.ID(user), token))
+	st.cookie.MaxAge = int(g.RememberLifetime / time.Second)
+	st.forget = false
+	return nil
+}
+
+// Logout logs out the user, regenerating the session and
+// invalidating the remember token.
+func (g *Guard) Logout(req *request.HTTP) error {
+	st, s, err := g.current(req)
+	if err != nil {
+		return err
+	}
+	if st.user != nil {
+		if err := g.Provider.SetRememberToken(st.user, ""); err != nil {
+			return err
+		}
+	}
+	s.Forget(g.sessionKey())
+	st.user, st.cookie, st.forget = nil, nil, true
+	return s.Regenerate()
+}
+
+// defaultGuard returns the default guard, which must be set.
+func defaultGuard() *Guard {
+	if Default == nil {
+		log.Fatalln("[PULSAR] There is no default guard, set auth.Default")
+	}
+	return Default
+}
+
+// Middleware loads the user of the request with the default guard.
+func Middleware(next router.Handler) router.Handler {
+	return defaultGuard().Middleware(next)
+}
+
+// RequireAuth rejects the guests with the default guard.
+func RequireAuth(next router.Handler) router.Handler {
+	return defaultGuard().RequireAuth(next)
+}
+
+// guard returns the innermost guard of the request.
+func guard(req *request.HTTP) (*Guard, error) {
+	if value, ok := req.Get(key); ok {
+		return value.(*state).guard, nil
+	}
+	return nil, ErrorNoGuard
+}
+
+// Attempt logs in the user with the innermost guard of the request.
+func Attempt(req *request.HTTP, identifier, password string, remember bool) (bool, error) {
+	g, err := guard(req)
+	if err != nil {
+		return false, err
+	}
+	return g.Attempt(req, identifier, password, remember)
+}
+
+// Login logs in the user with the innermost guard of the request.
+func Login(req *request.HTTP, user interface{}, remember bool) error {
+	g, err := guard(req)
+	if err != nil {
+		return err
+	}
+	return g.Login(req, user, remember)
+}
+
+// Logout logs out the user of the innermost guard of the request.
+func Logout(req *request.HTTP) error {
+	g, err := guard(req)
+	if err != nil {
+		return err
+	}
+	return g.Logout(req)
+}
+
+// Auth returns the user authenticated by the innermost guard of
+// the request, or nil for guests.
+func Auth(req *request.HTTP) interface{} {
+	if value, ok := req.Get(key); ok {
+		return value.(*state).user
+	}
+	return nil
+}
+
+// Check determines if the request has an authenticated user.
+func Check(req *request.HTTP) bool {
+	return Auth(req) != nil
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Hasher hashes and checks the user passwords.
+type Hasher interface {
+	// Hash returns the hash of the password.
+	Hash(password string) (string, error)
+	// Check determines if the password matches the hash.
+	Check(hash, password string) bool
+}
+
+// Bcrypt hashes the passwords with bcrypt.
+type Bcrypt struct {
+	// Cost is the bcrypt cost, bcrypt.DefaultCost when zero.
+	Cost int
+}
+
+// Hash returns the bcrypt hash of the password.
+func (b Bcrypt) Hash(password string) (string, error) {
+	cost := b.Cost
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	return string(hash), err
+}
+
+// Check determines if the password matches the bcrypt hash.
+func (Bcrypt) Check(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// Argon2 hashes the passwords with argon2id, encoded
+// as $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>.
+type Argon2 struct {
+	// Time is the number of passes, 1 when zero.
+	Time uint32
+	// Memory is the memory used in KiB, 64MB when zero.
+	Memory uint32
+	// Threads is the parallelism, 4 when zero.
+	Threads uint8
+}
+
+// Hash returns the argon2id hash of the password.
+func (a Argon2) Hash(password string) (string, error) {
+	if a.Time == 0 {
+		a.Time = 1
+	}
+	if a.Memory == 0 {
+		a.Memory = 64 * 1024
+	}
+	if a.Threads == 0 {
+		a.Threads = 4
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, a.Time, a.Memory, a.Threads, 32)
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, a.Memory, a.Time, a.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Check determines if the password matches the argon2id hash, using the
+// parameters stored in the hash, which must not be zero nor the key empty.
+func (Argon2) Check(hash, password string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false
+	}
+	var version int
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false
+	}
+	// Zero parameters make argon2 panic.
+	if memory == 0 || time == 0 || threads == 0 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+	// An empty key would match any password.
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false
+	}
+	other := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1
+}
+
+// DefaultHasher is the hasher used by Hash and the guards without one.
+var DefaultHasher Hasher = Bcrypt{}
+
+// Hash returns the hash of the password using the default hasher.
+func Hash(password string) (string, error) {
+	return DefaultHasher.Hash(password)
+}
+
+// Verify determines if the password matches the hash, which may have been
+// created with either bcrypt or argon2id, so the hasher can be changed
+// without invalidating the stored passwords.
+func Verify(hash, password string) bool {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		return Argon2{}.Check(hash, password)
+	}
+	return Bcrypt{}.Check(hash, password)
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashers(t *testing.T) {
+	hashers := []struct {
+		name   string
+		hasher Hasher
+		prefix string
+	}{
+		{"bcrypt", Bcrypt{Cost: 4}, "$2a$04$"},
+		{"argon2", Argon2{Memory: 1024}, "$argon2id$v=19$m=1024,t=1,p=4$"},
+	}
+	for _, tt := range hashers {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := tt.hasher.Hash("secret")
+			if err != nil {
+				t.Fatalf("Hash() error = %v", err)
+			}
+			if !strings.HasPrefix(hash, tt.prefix) {
+				t.Errorf("Hash() = %q, want the prefix %q", hash, tt.prefix)
+			}
+			tests := []struct {
+				password string
+				want     bool
+			}{
+				{"secret", true},
+				{"Secret", false},
+				{"", false},
+			}
+			for _, check := range tests {
+				if got := tt.hasher.Check(hash, check.password); got != check.want {
+					t.Errorf("Check(%q) = %v, want %v", check.password, got, check.want)
+				}
+				if got := Verify(hash, check.password); got != check.want {
+					t.Errorf("Verify(%q) = %v, want %v", check.password, got, check.want)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyInvalidHash(t *testing.T) {
+	hashes := []string{
+		"",
+		"secret",
+		"$argon2id$v=19$m=1024",
+		"$argon2id$v=18$m=1024,t=1,p=4$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=4$c2FsdA$",
+		"$argon2id$v=19$m=0,t=1,p=4$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=0,p=4$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=0$c2FsdA$a2V5",
+	}
+	for _, hash := range hashes {
+		if Verify(hash, "secret") {
+			t.Errorf("Verify(%q) = true, want false", hash)
+		}
+	}
+}
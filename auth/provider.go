@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"reflect"
+	"strings"
+
+	"github.com/pulsar-go/pulsar/db"
+)
+
+// Provider retrieves the users from a database model.
+type Provider struct {
+	// model is the user model type, a struct embedding db.Model.
+	model reflect.Type
+	// Identifier is the column used to find the user when logging in.
+	Identifier string
+	// Password is the column storing the password hash.
+	Password string
+	// Remember is the column storing the hash of the remember token.
+	Remember string
+	// DB is the database used, db.Builder when nil.
+	DB *db.DB
+}
+
+// NewProvider creates a provider of the model, which must be a pointer to a
+// struct embedding db.Model. It finds the users by their email column and
+// uses the password and remember_token columns.
+func NewProvider(model interface{}) *Provider {
+	t := reflect.TypeOf(model)
+	if t == nil || t.Kind() != reflect.Ptr || !db.EmbedsModel(t.Elem()) {
+		panic("auth: the user model must be a pointer to a struct embedding db.Model")
+	}
+	return &Provider{model: t.Elem(), Identifier: "email", Password: "password", Remember: "remember_token"}
+}
+
+// database returns the database used to find the users.
+func (p *Provider) database() *db.DB {
+	if p.DB != nil {
+		return p.DB
+	}
+	return db.Builder
+}
+
+// find returns the first user matching the condition, or nil if there's none.
+func (p *Provider) find(column string, value interface{}) (interface{}, error) {
+	user := reflect.New(p.model).Interface()
+	result := p.database().Where(column, value).First(user)
+	if result.RecordNotFound() {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return user, nil
+}
+
+// ByID returns the user with the id, or nil if there's none.
+func (p *Provider) ByID(id uint) (interface{}, error) {
+	return p.find("id", id)
+}
+
+// ByIdentifier returns the user with the identifier, like
+// the email, or nil if there's none.
+func (p *Provider) ByIdentifier(identifier string) (interface{}, error) {
+	return p.find(p.Identifier, identifier)
+}
+
+// ByRememberToken returns the user with the id and remember token,
+// or nil if there's none or the token doesn't match.
+func (p *Provider) ByRememberToken(id uint, token string) (interface{}, error) {
+	user, err := p.ByID(id)
+	if user == nil || err != nil {
+		return nil, err
+	}
+	stored := stringField(user, p.Remember)
+	if stored == "" || subtle.ConstantTimeCompare([]byte(stored), []byte(hashToken(token))) != 1 {
+		return nil, nil
+	}
+	return user, nil
+}
+
+// SetRememberToken stores the hash of the remember token of the
+// user, the empty token removes it.
+func (p *Provider) SetRememberToken(user interface{}, token string) error {
+	if token != "" {
+		token = hashToken(token)
+	}
+	if f := field(user, p.Remember); f.CanSet() && f.Kind() == reflect.String {
+		f.SetString(token)
+	}
+	return p.database().Model(user).UpdateColumn(p.Remember, token).Error
+}
+
+// ID returns the id of the user.
+func (p *Provider) ID(user interface{}) uint {
+	return uint(field(user, "id").Uint())
+}
+
+// PasswordHash returns the password hash of the user.
+func (p *Provider) PasswordHash(user interface{}) string {
+	return stringField(user, p.Password)
+}
+
+// field returns the field of the user model stored in the column, like
+// RememberToken for remember_token. It's invalid when there's none.
+func field(user interface{}, column string) reflect.Value {
+	name := strings.Replace(column, "_", "", -1)
+	return reflect.Indirect(reflect.ValueOf(user)).FieldByNameFunc(func(field string) bool {
+		return strings.EqualFold(field, name)
+	})
+}
+
+// stringField returns the string stored in the column, or
+// the empty string when the model has no such field.
+func stringField(user interface{}, column string) string {
+	if f := field(user, column); f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
+
+// hashToken returns the hash of a remember token stored in the database.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -4,10 +4,14 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-
-	"github.com/BurntSushi/toml"
 )
 
+// AppConfig specifies the configuration for the app file.
+type AppConfig struct {
+	Key          string   `toml:"key"`
+	PreviousKeys []string `toml:"previous_keys"`
+}
+
 // ServerConfig specifies the configuration for the server file.
 type ServerConfig struct {
 	Host             string   `toml:"host"`
@@ -18,6 +22,10 @@ type ServerConfig struct {
 	AllowedMethods   []string `toml:"allowed_methods"`
 	ExposedHeaders   []string `toml:"exposed_headers"`
 	AllowCredentials bool     `toml:"allow_credentials"`
+	ShutdownTimeout  string   `toml:"shutdown_timeout"`
+	RequestTimeout   string   `toml:"request_timeout"`
+	MaxBodySize      string   `toml:"max_body_size"`
+	BufferBody       bool     `toml:"buffer_body"`
 }
 
 // CertificateConfig specifies the configuration for the certificate file.
@@ -58,42 +66,92 @@ type QueueConfig struct {
 	Routines string `toml:"routines"`
 }
 
+// The environments known by pulsar.
+const (
+	Local      = "local"
+	Staging    = "staging"
+	Production = "production"
+	Testing    = "testing"
+)
+
+// EnvironmentVariable is the environment variable that selects the environment.
+const EnvironmentVariable = "PULSAR_ENV"
+
+// Environment returns the environment selected by PULSAR_ENV.
+func Environment() string {
+	return os.Getenv(EnvironmentVariable)
+}
+
+// UploadsConfig specifies the configuration for the uploads file.
+type UploadsConfig struct {
+	Path        string `toml:"path"`
+	MaxMemory   string `toml:"max_memory"`
+	MaxFileSize string `toml:"max_file_size"`
+}
+
+// SessionDrivers are the supported session stores.
+var SessionDrivers = []string{"memory", "file", "database", "cookie"}
+
+// SessionConfig specifies the configuration for the session file.
+type SessionConfig struct {
+	Driver   string `toml:"driver"`
+	Cookie   string `toml:"cookie"`
+	Lifetime string `toml:"lifetime"`
+	Path     string `toml:"path"`
+}
+
 // Config represents the pulsar server settings structure.
 type Config struct {
+	// Environment is the environment the settings were loaded for.
+	Environment string
+	App         AppConfig
 	Server      ServerConfig
 	Certificate CertificateConfig
 	Views       ViewsConfig
 	Database    DatabaseConfig
 	Mail        MailConfig
 	Queue       QueueConfig
+	Uploads     UploadsConfig
+	Session     SessionConfig
+	// unknown stores the keys found in the files that
+	// don't belong to any setting.
+	unknown Errors
+	// defaultViews is the default views path, which may not exist
+	// since the applications without views don't need it.
+	defaultViews string
 }
 
 // Settings define the global settings for pulsar.
-var Settings Config
+var Settings = Defaults()
 
-// @todo revisit with map[string]interface{} to make it dynamic
-func setConfigOf(file string, v interface{}) {
-	absPath, _ := filepath.Abs(filepath.Clean(filepath.Dir(os.Args[0]) + "/config/" + file + ".toml"))
-	if _, err := toml.DecodeFile(absPath, v); err != nil {
-		log.Fatalln("There was an error decoding file " + absPath + ", Error: " + err.Error())
+// Defaults returns the settings used for the missing
+// configuration files and keys.
+func Defaults() Config {
+	return Config{
+		Server:       ServerConfig{Port: "8080", ShutdownTimeout: "10s", MaxBodySize: "10MB"},
+		Certificate:  CertificateConfig{CertFile: "server.cert", KeyFile: "server.key"},
+		Views:        ViewsConfig{Path: "views"},
+		Queue:        QueueConfig{Routines: "10"},
+		Uploads:      UploadsConfig{Path: "uploads", MaxMemory: "32MB", MaxFileSize: "10MB"},
+		Session:      SessionConfig{Driver: "memory", Cookie: "pulsar_session", Lifetime: "2h", Path: "sessions"},
+		defaultViews: "views",
 	}
 }
 
-// Set sets the configuration from a configuration file.
+// Set sets the global settings from the configuration directory.
+func Set(dir string) error {
+	c, err := Load(dir)
+	if err != nil {
+		return err
+	}
+	Settings = *c
+	return nil
+}
+
+// init loads the global settings from the "config" directory
+// next to the executable, if there is any.
 func init() {
-	// Server config
-	setConfigOf("server", &Settings.Server)
-	// Certificate config
-	setConfigOf("certificate", &Settings.Certificate)
-	// Views config
-	setConfigOf("views", &Settings.Views)
-	// Database config
-	setConfigOf("database", &Settings.Database)
-	// Mail config
-	setConfigOf("mail", &Settings.Mail)
-	// Queue config
-	setConfigOf("queue", &Settings.Queue)
-	// Transform the relative paths into absolute.
-	Settings.Certificate.CertFile, _ = filepath.Abs(filepath.Dir(filepath.Dir(os.Args[0])+"/config") + "/" + filepath.Clean(Settings.Certificate.CertFile))
-	Settings.Certificate.KeyFile, _ = filepath.Abs(filepath.Dir(filepath.Dir(os.Args[0])+"/config") + "/" + filepath.Clean(Settings.Certificate.KeyFile))
+	if err := Set(filepath.Join(filepath.Dir(os.Args[0]), "config")); err != nil {
+		log.Println(err)
+	}
 }
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix is the prefix of the environment variables that override the settings.
+// A variable is named after its section and key, for example PULSAR_DATABASE_PASSWORD.
+const EnvPrefix = "PULSAR"
+
+// envName returns the environment variable name of a section key.
+func envName(section, key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(section) + "_" + strings.ToUpper(key)
+}
+
+// applyEnv overrides the section settings with the environment variables.
+// Lists are given as comma separated values.
+func applyEnv(section string, v interface{}) error {
+	value := reflect.ValueOf(v).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		key := value.Type().Field(i).Tag.Get("toml")
+		if key == "" {
+			continue
+		}
+		name := envName(section, key)
+		env, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		field := value.Field(i)
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(env)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(env)
+			if err != nil {
+				return fmt.Errorf("Environment variable %s must be a boolean, got '%s'", name, env)
+			}
+			field.SetBool(b)
+		case reflect.Slice:
+			list := []string{}
+			for _, item := range strings.Split(env, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+			field.Set(reflect.ValueOf(list))
+		}
+	}
+	return nil
+}
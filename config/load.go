@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// section represents a configuration file and the settings it's decoded into.
+type section struct {
+	name  string
+	value interface{}
+}
+
+// sections returns the configuration sections in the order they are loaded.
+func (c *Config) sections() []section {
+	return []section{
+		{"app", &c.App},
+		{"server", &c.Server},
+		{"certificate", &c.Certificate},
+		{"views", &c.Views},
+		{"database", &c.Database},
+		{"mail", &c.Mail},
+		{"queue", &c.Queue},
+		{"uploads", &c.Uploads},
+		{"session", &c.Session},
+	}
+}
+
+// Load loads the configuration files of the given directory for the
+// environment set in PULSAR_ENV. Missing files keep their default settings
+// and relative certificate and views paths are resolved against the parent of the directory.
+func Load(dir string) (*Config, error) {
+	return LoadEnvironment(dir, Environment())
+}
+
+// LoadEnvironment loads the configuration files of the given directory,
+// overlaying the <section>.<env>.toml files over the base ones.
+func LoadEnvironment(dir string, env string) (*Config, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	c, err := LoadEnvironmentFrom(http.Dir(absDir), env)
+	if err != nil {
+		return nil, err
+	}
+	// Transform the relative paths into absolute.
+	root := filepath.Dir(absDir)
+	c.Certificate.CertFile = absolute(root, c.Certificate.CertFile)
+	c.Certificate.KeyFile = absolute(root, c.Certificate.KeyFile)
+	c.Views.Path = absolute(root, c.Views.Path)
+	c.defaultViews = absolute(root, c.defaultViews)
+	return c, nil
+}
+
+// LoadFrom loads the configuration files from the given file system for the
+// environment set in PULSAR_ENV. Missing files keep their default settings
+// and environment variables override the file values.
+func LoadFrom(fs http.FileSystem) (*Config, error) {
+	return LoadEnvironmentFrom(fs, Environment())
+}
+
+// LoadEnvironmentFrom loads the configuration files from the given file system,
+// overlaying the <section>.<env>.toml files over the base ones.
+func LoadEnvironmentFrom(fs http.FileSystem, env string) (*Config, error) {
+	c := Defaults()
+	c.Environment = env
+	for _, s := range c.sections() {
+		if err := c.decodeSection(fs, s.name, s); err != nil {
+			return nil, err
+		}
+		if env != "" {
+			if err := c.decodeSection(fs, s.name+"."+env, s); err != nil {
+				return nil, err
+			}
+		}
+		if err := applyEnv(s.name, s.value); err != nil {
+			return nil, err
+		}
+	}
+	return &c, nil
+}
+
+// decodeSection decodes the given file into the section if it exists,
+// keeping track of the unknown keys so they're reported by Validate.
+func (c *Config) decodeSection(fs http.FileSystem, name string, s section) error {
+	file, err := fs.Open("/" + name + ".toml")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+	md, err := toml.DecodeReader(file, s.value)
+	if err != nil {
+		return fmt.Errorf("There was an error decoding file %s.toml, Error: %s", name, err)
+	}
+	for _, key := range md.Undecoded() {
+		c.unknown = append(c.unknown, &Error{File: name + ".toml", Key: key.String(), Message: "is not a known key"})
+	}
+	return nil
+}
+
+// absolute joins the path to the root unless it's already absolute.
+func absolute(root, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(root, filepath.Clean(path))
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// configDir creates a temporary config directory with the files.
+func configDir(t *testing.T, files map[string]string) string {
+	root, err := ioutil.TempDir("", "pulsar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(root, "config")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// setenv sets the environment variables and returns a function unsetting them.
+func setenv(vars map[string]string) func() {
+	for name, value := range vars {
+		os.Setenv(name, value)
+	}
+	return func() {
+		for name := range vars {
+			os.Unsetenv(name)
+		}
+	}
+}
+
+func TestLoadFrom(t *testing.T) {
+	dir := configDir(t, map[string]string{
+		"server.toml": `
+port = "3000"
+allowed_origins = ["https://example.com"]
+`,
+		"database.toml": `
+driver = "sqlite3"
+database = "app.db"
+`,
+	})
+	defer os.RemoveAll(filepath.Dir(dir))
+	tests := []struct {
+		name        string
+		vars        map[string]string
+		port        string
+		development bool
+		origins     []string
+		database    DatabaseConfig
+	}{
+		{
+			name:     "files",
+			port:     "3000",
+			origins:  []string{"https://example.com"},
+			database: DatabaseConfig{Driver: "sqlite3", Database: "app.db"},
+		},
+		{
+			name: "environment variables",
+			vars: map[string]string{
+				"PULSAR_SERVER_PORT":            "4000",
+				"PULSAR_SERVER_DEVELOPMENT":     "true",
+				"PULSAR_SERVER_ALLOWED_ORIGINS": "https://a.com, https://b.com,",
+				"PULSAR_DATABASE_PASSWORD":      "secret",
+			},
+			port:        "4000",
+			development: true,
+			origins:     []string{"https://a.com", "https://b.com"},
+			database:    DatabaseConfig{Driver: "sqlite3", Database: "app.db", Password: "secret"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer setenv(tt.vars)()
+			c, err := LoadFrom(http.Dir(dir))
+			if err != nil {
+				t.Fatalf("LoadFrom() error = %v", err)
+			}
+			if c.Server.Port != tt.port {
+				t.Errorf("Server.Port = %q, want %q", c.Server.Port, tt.port)
+			}
+			if c.Server.Development != tt.development {
+				t.Errorf("Server.Development = %v, want %v", c.Server.Development, tt.development)
+			}
+			if !reflect.DeepEqual(c.Server.AllowedOrigins, tt.origins) {
+				t.Errorf("Server.AllowedOrigins = %q, want %q", c.Server.AllowedOrigins, tt.origins)
+			}
+			if c.Database != tt.database {
+				t.Errorf("Database = %+v, want %+v", c.Database, tt.database)
+			}
+			// The sections without files keep their defaults.
+			if c.Server.ShutdownTimeout != "10s" || c.Queue.Routines != "10" {
+				t.Errorf("Defaults = %+v %+v, want them kept", c.Server, c.Queue)
+			}
+		})
+	}
+}
+
+func TestLoadEnvironmentFrom(t *testing.T) {
+	dir := configDir(t, map[string]string{
+		"server.toml": `
+port = "3000"
+development = true
+`,
+		"server.production.toml": `
+development = false
+`,
+		"database.toml": `
+driver = "sqlite3"
+database = "app.db"
+`,
+		"database.production.toml": `
+driver = "postgres"
+host = "db.example.com"
+`,
+	})
+	defer os.RemoveAll(filepath.Dir(dir))
+	tests := []struct {
+		name        string
+		env         string
+		vars        map[string]string
+		development bool
+		database    DatabaseConfig
+	}{
+		{
+			name:        "no environment",
+			development: true,
+			database:    DatabaseConfig{Driver: "sqlite3", Database: "app.db"},
+		},
+		{
+			name:     "overlay",
+			env:      Production,
+			database: DatabaseConfig{Driver: "postgres", Database: "app.db", Host: "db.example.com"},
+		},
+		{
+			name:        "missing overlay",
+			env:         Staging,
+			development: true,
+			database:    DatabaseConfig{Driver: "sqlite3", Database: "app.db"},
+		},
+		{
+			name:        "environment variables over the overlay",
+			env:         Production,
+			vars:        map[string]string{"PULSAR_SERVER_DEVELOPMENT": "true", "PULSAR_DATABASE_HOST": "localhost"},
+			development: true,
+			database:    DatabaseConfig{Driver: "postgres", Database: "app.db", Host: "localhost"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer setenv(tt.vars)()
+			c, err := LoadEnvironmentFrom(http.Dir(dir), tt.env)
+			if err != nil {
+				t.Fatalf("LoadEnvironmentFrom() error = %v", err)
+			}
+			if c.Environment != tt.env {
+				t.Errorf("Environment = %q, want %q", c.Environment, tt.env)
+			}
+			if c.Server.Port != "3000" {
+				t.Errorf("Server.Port = %q, want the base file value", c.Server.Port)
+			}
+			if c.Server.Development != tt.development {
+				t.Errorf("Server.Development = %v, want %v", c.Server.Development, tt.development)
+			}
+			if c.Database != tt.database {
+				t.Errorf("Database = %+v, want %+v", c.Database, tt.database)
+			}
+		})
+	}
+}
+
+func TestLoadEnvironmentFromErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		vars  map[string]string
+	}{
+		{
+			name:  "invalid file",
+			files: map[string]string{"server.toml": `port = `},
+		},
+		{
+			name:  "invalid overlay",
+			files: map[string]string{"server.production.toml": `port = `},
+		},
+		{
+			name: "invalid boolean variable",
+			vars: map[string]string{"PULSAR_SERVER_DEVELOPMENT": "maybe"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := configDir(t, tt.files)
+			defer os.RemoveAll(filepath.Dir(dir))
+			defer setenv(tt.vars)()
+			if _, err := LoadEnvironmentFrom(http.Dir(dir), Production); err == nil {
+				t.Error("LoadEnvironmentFrom() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestLoadPaths(t *testing.T) {
+	dir := configDir(t, map[string]string{
+		"certificate.toml": `key_file = "/etc/server.key"`,
+		"views.toml":       `path = "./templates"`,
+	})
+	defer os.RemoveAll(filepath.Dir(dir))
+	c, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := filepath.Join(filepath.Dir(dir), "server.cert"); c.Certificate.CertFile != want {
+		t.Errorf("Certificate.CertFile = %q, want %q", c.Certificate.CertFile, want)
+	}
+	if c.Certificate.KeyFile != "/etc/server.key" {
+		t.Errorf("Certificate.KeyFile = %q, want %q", c.Certificate.KeyFile, "/etc/server.key")
+	}
+	if want := filepath.Join(filepath.Dir(dir), "templates"); c.Views.Path != want {
+		t.Errorf("Views.Path = %q, want %q", c.Views.Path, want)
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import "sync/atomic"
+
+// Snapshot holds settings that are atomically replaced when they're
+// reloaded, so they can be read while the requests are served.
+type Snapshot struct {
+	value atomic.Value
+}
+
+// NewSnapshot creates a snapshot holding a copy of the settings.
+func NewSnapshot(c *Config) *Snapshot {
+	s := &Snapshot{}
+	s.Store(c)
+	return s
+}
+
+// Load returns the current settings, which must not be modified.
+func (s *Snapshot) Load() *Config {
+	c, _ := s.value.Load().(*Config)
+	return c
+}
+
+// Store replaces the current settings with a copy of the given ones.
+func (s *Snapshot) Store(c *Config) {
+	copied := *c
+	s.value.Store(&copied)
+}
+
+// global holds the global settings published by the default application.
+var global Snapshot
+
+// Global returns the snapshot of the global settings, which the
+// default application publishes when it starts serving.
+func Global() *Snapshot {
+	return &global
+}
+
+// Current returns the global settings published by the default application
+// while serving, or Settings otherwise. They must not be modified.
+func Current() *Config {
+	if c := global.Load(); c != nil {
+		return c
+	}
+	return &Settings
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Drivers are the supported database drivers.
+var Drivers = []string{"mysql", "postgres", "sqlite3"}
+
+// Error represents a problem with a configuration key.
+type Error struct {
+	File    string
+	Key     string
+	Message string
+}
+
+// Error returns the error message.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s %s", e.File, e.Key, e.Message)
+}
+
+// Errors represents all the problems found in the configuration.
+type Errors []*Error
+
+// Error returns all the error messages, one per line.
+func (e Errors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = "  " + err.Error()
+	}
+	return "Invalid configuration:\n" + strings.Join(lines, "\n")
+}
+
+// validator collects the errors of the configuration.
+type validator struct {
+	errors Errors
+}
+
+// add adds an error for the given section file and key.
+func (v *validator) add(section, key, format string, args ...interface{}) {
+	v.errors = append(v.errors, &Error{File: section + ".toml", Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+// port checks the value is a valid port number.
+func (v *validator) port(section, key, value string) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 0 || port > 65535 {
+		v.add(section, key, "must be a port number between 0 and 65535, got '%s'", value)
+	}
+}
+
+// directory checks the value is an existing directory.
+func (v *validator) directory(section, key, value string) {
+	info, err := os.Stat(value)
+	if err != nil || !info.IsDir() {
+		v.add(section, key, "must be an existing directory, got '%s'", value)
+	}
+}
+
+// Validate checks every section of the configuration and
+// reports all the problems found at once as Errors.
+func (c *Config) Validate() error {
+	v := &validator{errors: append(Errors{}, c.unknown...)}
+	// App config
+	for i, key := range append([]string{c.App.Key}, c.App.PreviousKeys...) {
+		if key != "" && len(key) < 32 {
+			name := "key"
+			if i > 0 {
+				name = fmt.Sprintf("previous_keys[%d]", i-1)
+			}
+			v.add("app", name, "must be at least 32 characters long")
+		}
+	}
+	// Server config
+	v.port("server", "port", c.Server.Port)
+	if c.Server.ShutdownTimeout != "" {
+		if _, err := time.ParseDuration(c.Server.ShutdownTimeout); err != nil {
+			v.add("server", "shutdown_timeout", "must be a duration like '10s', got '%s'", c.Server.ShutdownTimeout)
+		}
+	}
+	if c.Server.RequestTimeout != "" {
+		if _, err := time.ParseDuration(c.Server.RequestTimeout); err != nil {
+			v.add("server", "request_timeout", "must be a duration like '30s', got '%s'", c.Server.RequestTimeout)
+		}
+	}
+	if _, err := ParseSize(c.Server.MaxBodySize); err != nil {
+		v.add("server", "max_body_size", "must be a size like '10MB', got '%s'", c.Server.MaxBodySize)
+	}
+	// Certificate config
+	if c.Certificate.Enabled {
+		if c.Certificate.CertFile == "" {
+			v.add("certificate", "cert_file", "is required when enabled")
+		} else {
+			v.directory("certificate", "cert_file", filepath.Dir(c.Certificate.CertFile))
+		}
+		if c.Certificate.KeyFile == "" {
+			v.add("certificate", "key_file", "is required when enabled")
+		} else {
+			v.directory("certificate", "key_file", filepath.Dir(c.Certificate.KeyFile))
+		}
+	}
+	// Views config, the default directory is optional.
+	if c.Views.Path != "" && c.Views.Path != c.defaultViews {
+		v.directory("views", "path", c.Views.Path)
+	}
+	// Database config
+	if c.Database.Driver != "" {
+		supported := false
+		for _, driver := range Drivers {
+			supported = supported || driver == c.Database.Driver
+		}
+		if !supported {
+			v.add("database", "driver", "must be one of %s, got '%s'", strings.Join(Drivers, ", "), c.Database.Driver)
+		}
+		if c.Database.Database == "" {
+			v.add("database", "database", "is required")
+		}
+		if c.Database.Driver != "sqlite3" && c.Database.Port != "" {
+			v.port("database", "port", c.Database.Port)
+		}
+	}
+	// Mail config
+	if c.Mail.Port != "" {
+		v.port("mail", "port", c.Mail.Port)
+	}
+	if c.Mail.From != "" {
+		if _, err := mail.ParseAddress(c.Mail.From); err != nil {
+			v.add("mail", "from", "must be a valid address, got '%s'", c.Mail.From)
+		}
+	}
+	// Queue config
+	if routines, err := strconv.Atoi(c.Queue.Routines); err != nil || routines <= 0 {
+		v.add("queue", "routines", "must be a positive number, got '%s'", c.Queue.Routines)
+	}
+	// Uploads config
+	if c.Uploads.Path == "" {
+		v.add("uploads", "path", "is required")
+	}
+	if _, err := ParseSize(c.Uploads.MaxMemory); err != nil {
+		v.add("uploads", "max_memory", "must be a size like '32MB', got '%s'", c.Uploads.MaxMemory)
+	}
+	if _, err := ParseSize(c.Uploads.MaxFileSize); err != nil {
+		v.add("uploads", "max_file_size", "must be a size like '10MB', got '%s'", c.Uploads.MaxFileSize)
+	}
+	// Session config
+	supported := false
+	for _, driver := range SessionDrivers {
+		supported = supported || driver == c.Session.Driver
+	}
+	if !supported {
+		v.add("session", "driver", "must be one of %s, got '%s'", strings.Join(SessionDrivers, ", "), c.Session.Driver)
+	}
+	if c.Session.Cookie == "" {
+		v.add("session", "cookie", "is required")
+	}
+	if lifetime, err := time.ParseDuration(c.Session.Lifetime); err != nil || lifetime <= 0 {
+		v.add("session", "lifetime", "must be a duration like '2h', got '%s'", c.Session.Lifetime)
+	}
+	if c.Session.Driver == "file" && c.Session.Path == "" {
+		v.add("session", "path", "is required for the file driver")
+	}
+	if c.Session.Driver == "database" && c.Database.Driver == "" {
+		v.add("session", "driver", "requires a database to be configured")
+	}
+	if len(v.errors) > 0 {
+		return v.errors
+	}
+	return nil
+}
+
+// Validate checks the global settings.
+func Validate() error {
+	return Settings.Validate()
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*Config)
+		keys   []string
+	}{
+		{"defaults", func(*Config) {}, nil},
+		{"port", func(c *Config) { c.Server.Port = "http" }, []string{"server.toml port"}},
+		{"durations", func(c *Config) { c.Server.ShutdownTimeout = "10" }, []string{"server.toml shutdown_timeout"}},
+		{"certificate", func(c *Config) {
+			c.Certificate = CertificateConfig{Enabled: true, KeyFile: "/missing/server.key"}
+		}, []string{"certificate.toml cert_file", "certificate.toml key_file"}},
+		{"missing default views", func(c *Config) { c.defaultViews = "/missing/views"; c.Views.Path = "/missing/views" }, nil},
+		{"missing views", func(c *Config) { c.Views.Path = "/missing/views" }, []string{"views.toml path"}},
+		{"database", func(c *Config) {
+			c.Database = DatabaseConfig{Driver: "oracle", Port: "db"}
+		}, []string{"database.toml driver", "database.toml database", "database.toml port"}},
+		{"sqlite port", func(c *Config) {
+			c.Database = DatabaseConfig{Driver: "sqlite3", Database: "app.db", Port: "db"}
+		}, nil},
+		{"mail", func(c *Config) { c.Mail = MailConfig{Port: "smtp", From: "pulsar"} }, []string{"mail.toml port", "mail.toml from"}},
+		{"queue", func(c *Config) { c.Queue.Routines = "0" }, []string{"queue.toml routines"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Defaults()
+			tt.change(&c)
+			if keys := errorKeys(c.Validate()); !reflect.DeepEqual(keys, tt.keys) {
+				t.Errorf("Validate() keys = %q, want %q", keys, tt.keys)
+			}
+		})
+	}
+}
+
+func TestValidateUnknownKeys(t *testing.T) {
+	dir := configDir(t, map[string]string{
+		"server.toml":           `prot = "3000"`,
+		"queue.production.toml": `routine = "5"`,
+		// The overlays of other environments are not loaded.
+		"database.staging.toml": `hots = "localhost"`,
+	})
+	defer os.RemoveAll(filepath.Dir(dir))
+	c, err := LoadEnvironmentFrom(http.Dir(dir), Production)
+	if err != nil {
+		t.Fatalf("LoadEnvironmentFrom() error = %v", err)
+	}
+	want := []string{"server.toml prot", "queue.production.toml routine"}
+	if keys := errorKeys(c.Validate()); !reflect.DeepEqual(keys, want) {
+		t.Errorf("Validate() keys = %q, want %q", keys, want)
+	}
+}
+
+// errorKeys returns the file and key of every configuration error.
+func errorKeys(err error) []string {
+	if err == nil {
+		return nil
+	}
+	var keys []string
+	for _, e := range err.(Errors) {
+		keys = append(keys, e.File+" "+e.Key)
+	}
+	return keys
+}
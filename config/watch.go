@@ -0,0 +1,157 @@
+package config
+
+import (
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Subscriber is notified with the previous and the current
+// settings every time the configuration is reloaded.
+type Subscriber func(previous, current Config)
+
+// Watcher watches a configuration directory and reloads the sections that
+// can change without a restart: the CORS lists of the server, the views,
+// the mail and the queue. Changes to the other sections are only logged.
+type Watcher struct {
+	dir         string
+	target      *Snapshot
+	mutex       sync.Mutex
+	subscribers []Subscriber
+	modTimes    map[string]time.Time
+	stop        chan struct{}
+}
+
+// NewWatcher creates a watcher that reloads the given
+// directory into the target snapshot.
+func NewWatcher(dir string, target *Snapshot) *Watcher {
+	w := &Watcher{dir: dir, target: target}
+	w.modTimes = w.scan()
+	return w
+}
+
+// Subscribe registers subscribers notified after every reload.
+func (w *Watcher) Subscribe(subscribers ...Subscriber) *Watcher {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.subscribers = append(w.subscribers, subscribers...)
+	return w
+}
+
+// Start polls the directory for changes with the given interval until stopped.
+func (w *Watcher) Start(interval time.Duration) {
+	w.mutex.Lock()
+	if w.stop != nil {
+		w.mutex.Unlock()
+		return
+	}
+	w.stop = make(chan struct{})
+	stop := w.stop
+	w.mutex.Unlock()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if !w.changed() {
+					continue
+				}
+				if err := w.Reload(); err != nil {
+					log.Printf("[PULSAR] Configuration not reloaded: %s\n", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops polling the directory.
+func (w *Watcher) Stop() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+	}
+}
+
+// Reload loads and validates the directory, publishes a copy of the target
+// settings with the reloadable sections replaced and notifies the subscribers.
+// The published settings are never modified, so the requests can read them.
+func (w *Watcher) Reload() error {
+	w.mutex.Lock()
+	previous := w.target.Load()
+	loaded, err := LoadEnvironment(w.dir, previous.Environment)
+	if err == nil {
+		err = loaded.Validate()
+	}
+	if err != nil {
+		w.mutex.Unlock()
+		return err
+	}
+	warnRestart(previous, loaded)
+	current := *previous
+	current.Server.AllowedOrigins = loaded.Server.AllowedOrigins
+	current.Server.AllowedHeaders = loaded.Server.AllowedHeaders
+	current.Server.AllowedMethods = loaded.Server.AllowedMethods
+	current.Server.ExposedHeaders = loaded.Server.ExposedHeaders
+	current.Server.AllowCredentials = loaded.Server.AllowCredentials
+	current.Views = loaded.Views
+	current.Mail = loaded.Mail
+	current.Queue = loaded.Queue
+	w.target.Store(&current)
+	subscribers := append([]Subscriber(nil), w.subscribers...)
+	w.mutex.Unlock()
+	for _, subscriber := range subscribers {
+		subscriber(*previous, current)
+	}
+	return nil
+}
+
+// warnRestart logs the changed sections that need a restart to be applied.
+func warnRestart(previous, current *Config) {
+	p, c := previous.Server, current.Server
+	if p.Host != c.Host || p.Port != c.Port || p.Development != c.Development || p.ShutdownTimeout != c.ShutdownTimeout {
+		log.Println("[PULSAR] Changes to server.toml host, port, development or shutdown_timeout require a restart")
+	}
+	if previous.Certificate != current.Certificate {
+		log.Println("[PULSAR] Changes to certificate.toml require a restart")
+	}
+	if previous.Database != current.Database {
+		log.Println("[PULSAR] Changes to database.toml require a restart")
+	}
+}
+
+// scan returns the modification time of the configuration files.
+func (w *Watcher) scan() map[string]time.Time {
+	modTimes := make(map[string]time.Time)
+	files, err := ioutil.ReadDir(w.dir)
+	if err != nil {
+		return modTimes
+	}
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".toml") {
+			modTimes[filepath.Join(w.dir, file.Name())] = file.ModTime()
+		}
+	}
+	return modTimes
+}
+
+// changed determines if any configuration file was
+// created, modified or removed since the last scan.
+func (w *Watcher) changed() bool {
+	modTimes := w.scan()
+	changed := len(modTimes) != len(w.modTimes)
+	for file, modTime := range modTimes {
+		if previous, ok := w.modTimes[file]; !ok || !previous.Equal(modTime) {
+			changed = true
+		}
+	}
+	w.modTimes = modTimes
+	return changed
+}
@@ -0,0 +1,182 @@
+package cookie
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrorNoKey determines that there's no application key to sign or encrypt cookies.
+var ErrorNoKey = errors.New("There's no application key to sign or encrypt cookies")
+
+// ErrorInvalid determines that a cookie was tampered with or uses an unknown key.
+var ErrorInvalid = errors.New("The cookie is not valid")
+
+// Mode is how a cookie value is protected.
+type Mode uint
+
+// Indicate the available cookie modes.
+const (
+	// Plain cookies are sent as is.
+	Plain Mode = iota
+	// Signed cookies can be read by the client but not modified.
+	Signed
+	// Encrypted cookies can't be read nor modified by the client.
+	Encrypted
+)
+
+// keys are the keys derived from an application key.
+type keys struct {
+	sign    []byte
+	encrypt []byte
+}
+
+// Jar signs, encrypts and sets the defaults of the cookies.
+type Jar struct {
+	// keys are the current key followed by the previous ones.
+	keys []keys
+	// Secure is the default Secure attribute of the cookies.
+	Secure bool
+	// SameSite is the default SameSite attribute of the cookies.
+	SameSite http.SameSite
+}
+
+// NewJar creates a cookie jar that protects the cookies with the application key
+// and also accepts the cookies protected with the previous keys, so the key can
+// be rotated. Keys prefixed with "base64:" are decoded. Secure cookies should
+// be used when serving with TLS.
+func NewJar(key string, previous []string, secure bool) *Jar {
+	jar := &Jar{Secure: secure, SameSite: http.SameSiteLaxMode}
+	for _, k := range append([]string{key}, previous...) {
+		if k == "" {
+			continue
+		}
+		raw := []byte(k)
+		if strings.HasPrefix(k, "base64:") {
+			if decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(k, "base64:")); err == nil {
+				raw = decoded
+			}
+		}
+		jar.keys = append(jar.keys, keys{sign: derive(raw, "sign"), encrypt: derive(raw, "encrypt")})
+	}
+	return jar
+}
+
+// derive derives a 32 bytes key for the purpose from the application key.
+func derive(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("pulsar-cookie-" + purpose))
+	return mac.Sum(nil)
+}
+
+// New creates a cookie with the path "/" that's not accessible from scripts.
+func New(name, value string) *http.Cookie {
+	return &http.Cookie{Name: name, Value: value, Path: "/", HttpOnly: true}
+}
+
+// Prepare protects the cookie value with the mode and sets the default
+// Path, Secure and SameSite attributes if they're not set.
+func (j *Jar) Prepare(c *http.Cookie, mode Mode) error {
+	value, err := j.Encode(c.Name, c.Value, mode)
+	if err != nil {
+		return err
+	}
+	c.Value = value
+	if c.Path == "" {
+		c.Path = "/"
+	}
+	c.Secure = c.Secure || j.Secure
+	if c.SameSite == 0 {
+		c.SameSite = j.SameSite
+	}
+	return nil
+}
+
+// Encode protects the value of the named cookie with the mode.
+func (j *Jar) Encode(name, value string, mode Mode) (string, error) {
+	if mode == Plain {
+		return value, nil
+	}
+	if len(j.keys) == 0 {
+		return "", ErrorNoKey
+	}
+	k := j.keys[0]
+	if mode == Signed {
+		payload := append([]byte(value), sign(k.sign, name, []byte(value))...)
+		return base64.RawURLEncoding.EncodeToString(payload), nil
+	}
+	gcm, err := newGCM(k.encrypt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	// The name is authenticated so the value can't be moved to another cookie.
+	sealed := gcm.Seal(nonce, nonce, []byte(value), []byte(name))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decode returns the value of the named cookie protected with the mode,
+// trying the current key first and the previous ones after.
+func (j *Jar) Decode(name, value string, mode Mode) (string, error) {
+	if mode == Plain {
+		return value, nil
+	}
+	if len(j.keys) == 0 {
+		return "", ErrorNoKey
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return "", ErrorInvalid
+	}
+	for _, k := range j.keys {
+		if mode == Signed {
+			if len(payload) < sha256.Size {
+				return "", ErrorInvalid
+			}
+			data, mac := payload[:len(payload)-sha256.Size], payload[len(payload)-sha256.Size:]
+			if hmac.Equal(mac, sign(k.sign, name, data)) {
+				return string(data), nil
+			}
+			continue
+		}
+		gcm, err := newGCM(k.encrypt)
+		if err != nil {
+			return "", err
+		}
+		if len(payload) < gcm.NonceSize() {
+			return "", ErrorInvalid
+		}
+		nonce, sealed := payload[:gcm.NonceSize()], payload[gcm.NonceSize():]
+		if data, err := gcm.Open(nil, nonce, sealed, []byte(name)); err == nil {
+			return string(data), nil
+		}
+	}
+	return "", ErrorInvalid
+}
+
+// sign returns the signature of the value of the named cookie.
+func sign(key []byte, name string, value []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name + "="))
+	mac.Write(value)
+	return mac.Sum(nil)
+}
+
+// newGCM creates an AES-GCM cipher with the key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
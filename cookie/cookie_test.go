@@ -0,0 +1,139 @@
+package cookie
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	jar := NewJar("secret", nil, false)
+	tests := []struct {
+		name  string
+		value string
+		mode  Mode
+	}{
+		{"plain", "hello", Plain},
+		{"signed", "hello", Signed},
+		{"signed empty", "", Signed},
+		{"encrypted", "hello", Encrypted},
+		{"encrypted empty", "", Encrypted},
+		{"encrypted separators", "1|a=b;c", Encrypted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := jar.Encode("cookie", tt.value, tt.mode)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if tt.mode == Encrypted && tt.value != "" && encoded == tt.value {
+				t.Errorf("Encode() = %q, the value is not encrypted", encoded)
+			}
+			decoded, err := jar.Decode("cookie", encoded, tt.mode)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if decoded != tt.value {
+				t.Errorf("Decode() = %q, want %q", decoded, tt.value)
+			}
+		})
+	}
+}
+
+func TestDecodeTampered(t *testing.T) {
+	jar := NewJar("secret", nil, false)
+	tests := []struct {
+		name   string
+		mode   Mode
+		cookie string
+		tamper func(string) string
+	}{
+		{"signed garbage", Signed, "cookie", func(string) string { return "not base64!" }},
+		{"signed short", Signed, "cookie", func(string) string { return "c2hvcnQ" }},
+		{"signed flipped", Signed, "cookie", flip},
+		{"signed renamed", Signed, "other", func(v string) string { return v }},
+		{"encrypted garbage", Encrypted, "cookie", func(string) string { return "not base64!" }},
+		{"encrypted short", Encrypted, "cookie", func(string) string { return "c2hvcnQ" }},
+		{"encrypted flipped", Encrypted, "cookie", flip},
+		{"encrypted renamed", Encrypted, "other", func(v string) string { return v }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := jar.Encode("cookie", "hello", tt.mode)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if _, err := jar.Decode(tt.cookie, tt.tamper(encoded), tt.mode); err != ErrorInvalid {
+				t.Errorf("Decode() error = %v, want %v", err, ErrorInvalid)
+			}
+		})
+	}
+}
+
+// flip changes the last byte of an encoded value.
+func flip(value string) string {
+	payload, _ := base64.RawURLEncoding.DecodeString(value)
+	payload[len(payload)-1] ^= 1
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func TestRotation(t *testing.T) {
+	key := "base64:" + base64.StdEncoding.EncodeToString([]byte("old secret"))
+	old := NewJar(key, nil, false)
+	tests := []struct {
+		name string
+		jar  *Jar
+		err  error
+	}{
+		{"same key", NewJar(key, nil, false), nil},
+		{"decoded key", NewJar("old secret", nil, false), nil},
+		{"previous key", NewJar("new secret", []string{"other", key}, false), nil},
+		{"dropped key", NewJar("new secret", []string{"other"}, false), ErrorInvalid},
+		{"no key", NewJar("", nil, false), ErrorNoKey},
+	}
+	for _, mode := range []Mode{Signed, Encrypted} {
+		encoded, err := old.Encode("cookie", "hello", mode)
+		if err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				decoded, err := tt.jar.Decode("cookie", encoded, mode)
+				if err != tt.err {
+					t.Fatalf("Decode() error = %v, want %v", err, tt.err)
+				}
+				if err == nil && decoded != "hello" {
+					t.Errorf("Decode() = %q, want %q", decoded, "hello")
+				}
+			})
+		}
+	}
+}
+
+func TestRotationEncodesWithCurrentKey(t *testing.T) {
+	jar := NewJar("new secret", []string{"old secret"}, false)
+	encoded, err := jar.Encode("cookie", "hello", Signed)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if _, err := NewJar("new secret", nil, false).Decode("cookie", encoded, Signed); err != nil {
+		t.Errorf("Decode() with the current key error = %v", err)
+	}
+	if _, err := NewJar("old secret", nil, false).Decode("cookie", encoded, Signed); err != ErrorInvalid {
+		t.Errorf("Decode() with the previous key error = %v, want %v", err, ErrorInvalid)
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	jar := NewJar("secret", nil, true)
+	c := &http.Cookie{Name: "cookie", Value: "hello"}
+	if err := jar.Prepare(c, Encrypted); err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	if c.Path != "/" || !c.Secure || c.SameSite != http.SameSiteLaxMode {
+		t.Errorf("Prepare() = %+v, want the jar defaults", c)
+	}
+	if value, err := jar.Decode("cookie", c.Value, Encrypted); err != nil || value != "hello" {
+		t.Errorf("Decode() = %q, %v, want %q", value, err, "hello")
+	}
+}
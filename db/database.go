@@ -40,12 +40,20 @@ func AddModels(models ...interface{}) {
 	Models = append(Models, models...)
 }
 
-// Open opens a new database connection.
+// Open opens a new database connection using the global settings.
 func Open() {
+	database, err := Connect(&config.Settings.Database)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	Builder = database
+}
+
+// Connect opens a new database connection using the given settings.
+func Connect(s *config.DatabaseConfig) (*DB, error) {
 	// Create the arguments
 	var args string
-	// Copy to reduce code size.
-	s := &config.Settings.Database
 	switch s.Driver {
 	case "mysql":
 		args = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", s.User, s.Password, s.Host, s.Port, s.Database)
@@ -54,19 +62,19 @@ func Open() {
 	case "sqlite3":
 		f, err := filepath.Abs(filepath.Dir(os.Args[0]) + "/" + s.Database)
 		if err != nil {
-			log.Fatalf("Unable to get path of database %s\n", s.Database)
+			return nil, fmt.Errorf("Unable to get path of database %s", s.Database)
 		}
 		args = f
 	default:
-		log.Fatalf("Database driver '%s' is not supported.\n", s.Driver)
+		return nil, fmt.Errorf("Database driver '%s' is not supported", s.Driver)
 	}
 	// Open the database
 	dbOpened, err := gorm.Open(s.Driver, args)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
 	}
 
-	Builder = &DB{dbOpened}
+	return &DB{dbOpened}, nil
 }
 
 // clone creates a new instance of the DB
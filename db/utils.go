@@ -1,6 +1,12 @@
 package db
 
-import "strings"
+import (
+	"reflect"
+	"strings"
+)
+
+// modelType is the type of the base database model.
+var modelType = reflect.TypeOf(Model{})
 
 // IsNew checks if the model was already saved
 func (b *DB) IsNew(model interface{}) bool {
@@ -15,3 +21,16 @@ func enhanceQuery(query interface{}) interface{} {
 
 	return query
 }
+
+// EmbedsModel determines if the type is a struct embedding Model.
+func EmbedsModel(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Anonymous && field.Type == modelType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package pulsar
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+	"github.com/pulsar-go/pulsar/router"
+)
+
+// errorHandler adapts a route handler to a http handler.
+func errorHandler(handler router.Handler, options requestOptions) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := options.request(w, r, nil)
+		res := handler(req)
+		res.Handle(req)
+	})
+}
+
+// developmentRecover responds with the panic and its stack trace.
+func developmentRecover(req *request.HTTP, recovered interface{}) response.HTTP {
+	return response.TextWithCode(fmt.Sprintf("panic: %v\n\n%s", recovered, debug.Stack()), http.StatusInternalServerError)
+}
+
+// productionRecover responds with a generic error.
+func productionRecover(req *request.HTTP, recovered interface{}) response.HTTP {
+	return response.TextWithCode(http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// registerErrorHandlers registers the not found, method not allowed
+// and recover handlers of the router.
+func registerErrorHandlers(mux *httprouter.Router, r *router.Router, development bool, options requestOptions) {
+	if r.NotFoundHandler != nil {
+		mux.NotFound = errorHandler(r.NotFoundHandler, options)
+	}
+	if r.MethodNotAllowedHandler != nil {
+		mux.MethodNotAllowed = errorHandler(r.MethodNotAllowedHandler, options)
+	}
+	recoverHandler := r.RecoverHandler
+	if recoverHandler == nil {
+		if development {
+			recoverHandler = developmentRecover
+		} else {
+			recoverHandler = productionRecover
+		}
+	}
+	mux.PanicHandler = func(w http.ResponseWriter, hr *http.Request, recovered interface{}) {
+		log.Printf("[PULSAR] Recovered from panic in %s %s: %v\n", hr.Method, hr.URL, recovered)
+		req := options.request(w, hr, nil)
+		res := recoverHandler(req, recovered)
+		res.Handle(req)
+	}
+}
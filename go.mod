@@ -1,17 +1,22 @@
 module github.com/pulsar-go/pulsar
 
-go 1.12
+go 1.17
 
 require (
 	github.com/BurntSushi/toml v0.3.1
-	github.com/go-sql-driver/mysql v1.4.1 // indirect
 	github.com/jinzhu/gorm v1.9.2
-	github.com/jinzhu/inflection v0.0.0-20180308033659-04140366298a // indirect
 	github.com/jordan-wright/email v0.0.0-20190218024454-3ea4d25e7cf8
 	github.com/julienschmidt/httprouter v1.2.0
 	github.com/kabukky/httpscerts v0.0.0-20150320125433-617593d7dcb3
-	github.com/lib/pq v1.0.0 // indirect
-	github.com/mattn/go-sqlite3 v1.10.0 // indirect
 	github.com/panjf2000/ants v1.0.0
 	github.com/rs/cors v1.3.0
+	golang.org/x/crypto v0.11.0
+)
+
+require (
+	github.com/go-sql-driver/mysql v1.4.1 // indirect
+	github.com/jinzhu/inflection v0.0.0-20180308033659-04140366298a // indirect
+	github.com/lib/pq v1.0.0 // indirect
+	github.com/mattn/go-sqlite3 v1.10.0 // indirect
+	golang.org/x/sys v0.10.0 // indirect
 )
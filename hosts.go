@@ -0,0 +1,89 @@
+package pulsar
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pulsar-go/pulsar/router"
+)
+
+// domainParamsKey is the request context key of the captured domain placeholders.
+type domainParamsKey struct{}
+
+// domainMux is the router of the routes of a domain.
+type domainMux struct {
+	domain *router.Domain
+	mux    *httprouter.Router
+}
+
+// hostRouter dispatches the requests to the router of the first domain
+// matching their host that has a route for them, or to the fallback router
+// with the routes without a domain, which serve every host.
+type hostRouter struct {
+	fallback *httprouter.Router
+	domains  []*domainMux
+	// setup configures every new domain router.
+	setup func(mux *httprouter.Router)
+}
+
+// mux returns the router of the domain pattern, creating it if needed.
+func (h *hostRouter) mux(pattern string) *httprouter.Router {
+	if pattern == "" {
+		return h.fallback
+	}
+	for _, d := range h.domains {
+		if d.domain.Pattern == pattern {
+			return d.mux
+		}
+	}
+	mux := httprouter.New()
+	h.setup(mux)
+	h.domains = append(h.domains, &domainMux{domain: router.ParseDomain(pattern), mux: mux})
+	return mux
+}
+
+// ServeHTTP dispatches the request to the router of its host. When no
+// router has a route for it, the first domain matching the host responds
+// unless the fallback router has one, so the domain routes keep their
+// method not allowed and redirect responses.
+func (h *hostRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var matched *domainMux
+	var matchedRequest *http.Request
+	for _, d := range h.domains {
+		params, ok := d.domain.Match(r.Host)
+		if !ok {
+			continue
+		}
+		req := r.WithContext(context.WithValue(r.Context(), domainParamsKey{}, params))
+		if handle, _, _ := d.mux.Lookup(r.Method, r.URL.Path); handle != nil {
+			d.mux.ServeHTTP(w, req)
+			return
+		}
+		if matched == nil {
+			matched, matchedRequest = d, req
+		}
+	}
+	if matched != nil {
+		if handle, _, _ := h.fallback.Lookup(r.Method, r.URL.Path); handle == nil {
+			matched.mux.ServeHTTP(w, matchedRequest)
+			return
+		}
+	}
+	h.fallback.ServeHTTP(w, r)
+}
+
+// handler returns the host router, or just the fallback router
+// when there are no domain routes.
+func (h *hostRouter) handler() http.Handler {
+	if len(h.domains) == 0 {
+		return h.fallback
+	}
+	return h
+}
+
+// domainParams returns the domain placeholders captured for the request.
+func domainParams(r *http.Request) httprouter.Params {
+	params, _ := r.Context().Value(domainParamsKey{}).(httprouter.Params)
+	return params
+}
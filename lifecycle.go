@@ -0,0 +1,45 @@
+package pulsar
+
+import "log"
+
+// Hook represents a function that runs at a certain point of the server lifecycle.
+type Hook func() error
+
+// OnStart registers hooks that run when the application server starts.
+func (app *App) OnStart(hooks ...Hook) *App {
+	app.hooksMutex.Lock()
+	defer app.hooksMutex.Unlock()
+	app.startHooks = append(app.startHooks, hooks...)
+	return app
+}
+
+// OnShutdown registers hooks that run after the application server stopped accepting
+// requests and the queued jobs have finished, but before the database is closed.
+func (app *App) OnShutdown(hooks ...Hook) *App {
+	app.hooksMutex.Lock()
+	defer app.hooksMutex.Unlock()
+	app.shutdownHooks = append(app.shutdownHooks, hooks...)
+	return app
+}
+
+// OnStart registers hooks that run when the default application server starts.
+func OnStart(hooks ...Hook) {
+	defaultApp.OnStart(hooks...)
+}
+
+// OnShutdown registers hooks that run when the default application server shuts down.
+func OnShutdown(hooks ...Hook) {
+	defaultApp.OnShutdown(hooks...)
+}
+
+// runHooks runs the given hooks in order, logging the ones that fail.
+func (app *App) runHooks(name string, hooks *[]Hook) {
+	app.hooksMutex.Lock()
+	list := append([]Hook(nil), *hooks...)
+	app.hooksMutex.Unlock()
+	for _, hook := range list {
+		if err := hook(); err != nil {
+			log.Printf("[PULSAR] %s hook failed: %s\n", name, err)
+		}
+	}
+}
@@ -11,16 +11,63 @@ import (
 	"github.com/jordan-wright/email"
 )
 
+// Mailer sends mails using a mail configuration and a queue.
+type Mailer struct {
+	// Config is the mail configuration. The mail section of
+	// the settings is used when nil.
+	Config *config.MailConfig
+	// Settings are the settings used when there's no mail
+	// configuration, the global settings when nil.
+	Settings *config.Snapshot
+	// Queue is used to send the mails in the background.
+	// The default queue is used when nil.
+	Queue *queue.Queue
+}
+
 // Mail exposes the Mail type.
 type Mail struct {
 	Message *email.Email
-	// Reserved for future things.
+	mailer  *Mailer
+}
+
+// Default is the mailer used by the package level functions.
+var Default = &Mailer{}
+
+// NewMailer creates a new mailer with the given configuration and queue.
+func NewMailer(c *config.MailConfig, q *queue.Queue) *Mailer {
+	return &Mailer{Config: c, Queue: q}
+}
+
+// settings returns the mail configuration of the mailer.
+func (m *Mailer) settings() *config.MailConfig {
+	if m.Config != nil {
+		return m.Config
+	}
+	if m.Settings != nil {
+		if c := m.Settings.Load(); c != nil {
+			return &c.Mail
+		}
+	}
+	return &config.Current().Mail
+}
+
+// dispatch runs the handler in the mailer queue.
+func (m *Mailer) dispatch(handler func()) error {
+	if m.Queue == nil {
+		return queue.Dispatch(handler)
+	}
+	return m.Queue.Dispatch(handler)
+}
+
+// Create creates a new empty mail that is sent using the mailer.
+func (m *Mailer) Create() *Mail {
+	mail := &Mail{Message: email.NewEmail(), mailer: m}
+	return mail.From(m.settings().From)
 }
 
 // Create creates a new empty mail.
 func Create() *Mail {
-	mail := &Mail{Message: email.NewEmail()}
-	return mail.From(config.Settings.Mail.From)
+	return Default.Create()
 }
 
 // ReplyTo determines where the message will get replied to.
@@ -95,15 +142,15 @@ func (m *Mail) AttachFile(filename string) *Mail {
 // SendNow sends the mail.
 func (m *Mail) SendNow() error {
 	// Copy to keep the code length considerable.
-	s := &config.Settings.Mail
+	s := m.mailer.settings()
 	return m.Message.Send(s.Host+":"+s.Port, smtp.PlainAuth(s.Identity, s.Username, s.Password, s.Host))
 }
 
 // Send sends the mail.
 func (m *Mail) Send() error {
 	// Copy to keep the code length considerable.
-	s := &config.Settings.Mail
-	return queue.Dispatch(func() {
+	s := m.mailer.settings()
+	return m.mailer.dispatch(func() {
 		err := m.Message.Send(s.Host+":"+s.Port, smtp.PlainAuth(s.Identity, s.Username, s.Password, s.Host))
 		if err != nil {
 			log.Println(err)
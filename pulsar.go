@@ -1,22 +1,17 @@
 package pulsar
 
 import (
-	"bytes"
-	"fmt"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
-	"strconv"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/kabukky/httpscerts"
 	"github.com/pulsar-go/pulsar/config"
-	"github.com/pulsar-go/pulsar/db"
-	"github.com/pulsar-go/pulsar/queue"
+	"github.com/pulsar-go/pulsar/cookie"
 	"github.com/pulsar-go/pulsar/request"
 	"github.com/pulsar-go/pulsar/router"
-	"github.com/rs/cors"
 )
 
 // fileExists determines if a file exists in a given path.
@@ -25,139 +20,200 @@ func fileExists(path string) bool {
 	return !os.IsNotExist(err)
 }
 
+// requestOptions determines how the requests are created.
+type requestOptions struct {
+	limit   int64
+	buffer  bool
+	timeout time.Duration
+	uploads request.Uploads
+	cookies *cookie.Jar
+	// settings returns the current application settings.
+	settings func() *config.Config
+	// router is the root router of the application.
+	router *router.Router
+}
+
+// newRequestOptions returns the request options of the settings.
+func newRequestOptions(c *config.Config, settings func() *config.Config, r *router.Router) requestOptions {
+	s, u := &c.Server, &c.Uploads
+	limit, err := config.ParseSize(s.MaxBodySize)
+	if err != nil {
+		log.Printf("[PULSAR] Invalid max body size %s, the request bodies won't be limited\n", s.MaxBodySize)
+	}
+	var timeout time.Duration
+	if s.RequestTimeout != "" {
+		if timeout, err = time.ParseDuration(s.RequestTimeout); err != nil {
+			log.Printf("[PULSAR] Invalid request timeout %s, the requests won't have a deadline\n", s.RequestTimeout)
+		}
+	}
+	// The uploads settings are validated with the configuration.
+	memory, _ := config.ParseSize(u.MaxMemory)
+	fileSize, _ := config.ParseSize(u.MaxFileSize)
+	return requestOptions{
+		limit:    limit,
+		buffer:   s.BufferBody,
+		timeout:  timeout,
+		uploads:  request.Uploads{Dir: u.Path, MaxMemory: memory, MaxFileSize: fileSize},
+		cookies:  cookie.NewJar(c.App.Key, c.App.PreviousKeys, c.Certificate.Enabled),
+		settings: settings,
+		router:   r,
+	}
+}
+
+// request creates the request of the application, without limiting its body.
+func (options requestOptions) request(w http.ResponseWriter, r *http.Request, ps httprouter.Params) *request.HTTP {
+	return &request.HTTP{
+		Request:      r,
+		Writer:       w,
+		Params:       ps,
+		DomainParams: domainParams(r),
+		Uploads:      options.uploads,
+		Cookies:      options.cookies,
+		Config:       options.settings(),
+		// The router database is set when the application opens it.
+		DB:  options.router.Database,
+		URL: options.router.URL,
+	}
+}
+
+// newRequest creates the request given to the route handler, limiting its body
+// and buffering it if needed. It returns false when it already responded.
+func newRequest(w http.ResponseWriter, r *http.Request, ps httprouter.Params, options requestOptions) (*request.HTTP, bool) {
+	req := options.request(w, r, ps)
+	if options.limit > 0 && r.ContentLength > options.limit {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+	req.LimitBody(options.limit)
+	if options.buffer {
+		if _, err := req.ReadBody(); err != nil {
+			if err == request.ErrorBodyTooLarge {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			} else {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			}
+			return nil, false
+		}
+	}
+	return req, true
+}
+
+// removeMultipartForm removes the temporary files of the parsed multipart form.
+// The request context is replaced with shallow copies of the http.Request and
+// net/http only removes the files of the form parsed in the original one.
+func removeMultipartForm(req *request.HTTP) {
+	if form := req.Request.MultipartForm; form != nil {
+		if err := form.RemoveAll(); err != nil {
+			log.Printf("[PULSAR] Unable to remove the uploaded files: %s\n", err)
+		}
+	}
+}
+
 // debugHandler is responsible for each http handler in debug mode.
-func developmentHandler(route *router.Route) func(http.ResponseWriter, *http.Request, httprouter.Params) {
+func developmentHandler(route *router.Route, options requestOptions) func(http.ResponseWriter, *http.Request, httprouter.Params) {
+	handler := route.Build()
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		log.Printf("[PULSAR] Request %s\n", r.URL)
-		req := &request.HTTP{Request: r, Writer: w, Params: ps}
-		buff, err := ioutil.ReadAll(req.Request.Body)
-		if err != nil {
-			log.Printf("[PULSAR] Failed to read the request body\n")
+		req, ok := newRequest(w, r, ps, options)
+		if !ok {
+			log.Printf("[PULSAR] Rejected the request body of %s\n", r.URL)
+			return
+		}
+		defer removeMultipartForm(req)
+		if options.timeout > 0 {
+			defer req.WithTimeout(options.timeout)()
 		}
-		req.Body = string(buff)
-		req.Request.Body = ioutil.NopCloser(bytes.NewBuffer(buff))
-		res := route.Handler(req)
+		res := handler(req)
 		res.Handle(req)
 	}
 }
 
 // productionHandler is responsible for each http handler in debug mode.
-func productionHandler(route *router.Route) func(http.ResponseWriter, *http.Request, httprouter.Params) {
+func productionHandler(route *router.Route, options requestOptions) func(http.ResponseWriter, *http.Request, httprouter.Params) {
+	handler := route.Build()
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-		req := &request.HTTP{Request: r, Writer: w, Params: ps}
-		buff, _ := ioutil.ReadAll(req.Request.Body)
-		req.Body = string(buff)
-		req.Request.Body = ioutil.NopCloser(bytes.NewBuffer(buff))
-		res := route.Handler(req)
+		req, ok := newRequest(w, r, ps, options)
+		if !ok {
+			return
+		}
+		defer removeMultipartForm(req)
+		if options.timeout > 0 {
+			defer req.WithTimeout(options.timeout)()
+		}
+		res := handler(req)
 		res.Handle(req)
 	}
 }
 
-// RegisterRoutes registers the routes.
-func RegisterRoutes(mux *httprouter.Router, r *router.Router) {
+// RegisterRoutes registers the routes. The routes without domain are registered
+// in the given mux, and the returned handler dispatches the requests to the
+// routes of the domain matching their host or to the mux otherwise.
+func RegisterRoutes(mux *httprouter.Router, r *router.Router) http.Handler {
+	return newHostRouter(mux, r, &config.Settings, config.Current).handler()
+}
+
+// newHostRouter registers the router routes in the mux of their domain. The
+// requests are served with the settings returned by the settings function.
+func newHostRouter(fallback *httprouter.Router, r *router.Router, c *config.Config, settings func() *config.Config) *hostRouter {
+	options := newRequestOptions(c, settings, r)
+	hosts := &hostRouter{fallback: fallback, setup: func(mux *httprouter.Router) {
+		registerErrorHandlers(mux, r, c.Server.Development, options)
+	}}
+	hosts.setup(fallback)
+	registerRoutes(hosts, r, c.Server.Development, options)
+	return hosts
+}
+
+// registerRoutes registers the routes using the development
+// or the production handler.
+func registerRoutes(hosts *hostRouter, r *router.Router, development bool, options requestOptions) {
 	// Register the routes.
-	var handler func(*router.Route) func(http.ResponseWriter, *http.Request, httprouter.Params)
-	if config.Settings.Server.Development {
+	var handler func(*router.Route, requestOptions) func(http.ResponseWriter, *http.Request, httprouter.Params)
+	if development {
 		handler = developmentHandler
 	} else {
 		handler = productionHandler
 	}
 	for _, element := range r.Routes {
 		route := element
-		switch route.Method {
-		case request.GetRequest:
-			mux.GET(route.URI, handler(&route))
-		case request.HeadRequest:
-			mux.HEAD(route.URI, handler(&route))
-		case request.PostRequest:
-			mux.POST(route.URI, handler(&route))
-		case request.PutRequest:
-			mux.PUT(route.URI, handler(&route))
-		case request.PatchRequest:
-			mux.PATCH(route.URI, handler(&route))
-		case request.DeleteRequest:
-			mux.DELETE(route.URI, handler(&route))
+		// Alias routes are served by the route they belong to.
+		if route.Alias {
+			continue
 		}
+		hosts.mux(route.Domain).Handle(route.HTTPMethod(), route.URI, handler(&route, options))
 	}
 	// Register his childs.
 	for _, element := range r.Childs {
-		RegisterRoutes(mux, element)
+		registerRoutes(hosts, element, development, options)
 	}
 }
 
-// Serve starts the server.
+// Serve starts the default application server and blocks until it fails
+// or until a SIGINT or SIGTERM signal gracefully shuts it down.
 func Serve() error {
-	router := &router.Routes
-	mux := httprouter.New()
-	// Register the application routes.
-	RegisterRoutes(mux, router)
-	// Register the CORS
-	handler := cors.New(cors.Options{
-		AllowedOrigins:     config.Settings.Server.AllowedOrigins,
-		AllowedHeaders:     config.Settings.Server.AllowedHeaders,
-		AllowedMethods:     config.Settings.Server.AllowedMethods,
-		AllowCredentials:   config.Settings.Server.AllowCredentials,
-		ExposedHeaders:     config.Settings.Server.ExposedHeaders,
-		Debug:              config.Settings.Server.Development,
-		OptionsPassthrough: true,
-	}).Handler(mux)
-	// Set the address of the server.
-	address := config.Settings.Server.Host + ":" + config.Settings.Server.Port
-	// Generate SSL.
-	generateSSLCertificate(address)
-	// Set the database configuration
-	db.Open()
-	defer db.Builder.Close()
-	// Migrate if nessesary
-	if config.Settings.Database.AutoMigrate {
-		db.Builder.AutoMigrate(db.Models...)
-	}
-	// Configure the queue system.
-	routines, err := strconv.ParseInt(config.Settings.Queue.Routines, 10, 32)
-	if err != nil {
-		log.Fatal(err)
-	}
-	queue.NewPool(int(routines))
-	defer queue.Pool.Release()
-	if config.Settings.Server.Development {
-		fmt.Println("-----------------------------------------------------")
-		fmt.Println("|                                                   |")
-		fmt.Println("|  P U L S A R                                      |")
-		fmt.Println("|  Go Web framework                                 |")
-		fmt.Println("|                                                   |")
-		fmt.Println("|  Erik Campobadal <soc@erik.cat>                   |")
-		fmt.Println("|  Krishan König <krishan.koenig@googlemail.com>    |")
-		fmt.Println("|                                                   |")
-		fmt.Println("-----------------------------------------------------")
-		fmt.Println()
-	}
-	if config.Settings.Certificate.Enabled {
-		if config.Settings.Server.Development {
-			fmt.Printf("Creating a HTTP/2 server with TLS on %s\n", address)
-			fmt.Printf("Certificate: %s\nKey: %s\n\n", config.Settings.Certificate.CertFile, config.Settings.Certificate.KeyFile)
-		}
-		return http.ListenAndServeTLS(address, config.Settings.Certificate.CertFile, config.Settings.Certificate.KeyFile, handler)
-	}
-	if config.Settings.Server.Development {
-		fmt.Printf("Creating a HTTP/1.1 server on %s\n\n", address)
-	}
-	return http.ListenAndServe(address, handler)
+	return defaultApp.Serve()
+}
+
+// Watch reloads the default application configuration from
+// the given directory while the server is running.
+func Watch(dir string) {
+	defaultApp.Watch(dir)
 }
 
 // generateSSLCertificate creates an ssl certificate if https is enabled
-func generateSSLCertificate(address string) {
+func generateSSLCertificate(c *config.CertificateConfig, address string) {
 	// Generate a SSL certificate if needed.
-	if !config.Settings.Certificate.Enabled {
+	if !c.Enabled {
 		return
 	}
 
-	err := httpscerts.Check(config.Settings.Certificate.CertFile, config.Settings.Certificate.KeyFile)
+	err := httpscerts.Check(c.CertFile, c.KeyFile)
 	if err == nil {
 		return
 	}
 
 	// If they are not available, generate new ones.
-	err = httpscerts.Generate(config.Settings.Certificate.CertFile, config.Settings.Certificate.KeyFile, address)
+	err = httpscerts.Generate(c.CertFile, c.KeyFile, address)
 	if err != nil {
 		log.Fatal("Unable to create HTTP certificates.")
 	}
@@ -1,18 +1,113 @@
 package queue
 
-import "github.com/panjf2000/ants"
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/panjf2000/ants"
+)
+
+// ErrorClosed is returned when dispatching a job once the queue is waiting
+// for its jobs to finish.
+var ErrorClosed = errors.New("The queue is closed and doesn't accept new jobs")
+
+// Queue represents a pool of goroutines that run the dispatched jobs.
+type Queue struct {
+	Pool *ants.Pool
+	// pending keeps track of the jobs that have been dispatched but not finished.
+	pending sync.WaitGroup
+	// closed determines if Wait was called, so new jobs are rejected
+	// instead of being added to pending while it's waited on.
+	closed bool
+	mutex  sync.Mutex
+}
+
+// Default defines the default queue
+var Default *Queue
 
 // Pool defines the default queue pool
 var Pool *ants.Pool
 
+// New creates a new queue with the given number of routines.
+func New(number int) (*Queue, error) {
+	pool, err := ants.NewPool(number)
+	if err != nil {
+		return nil, err
+	}
+	return &Queue{Pool: pool}, nil
+}
+
 // NewPool creates the default queue pool
 func NewPool(number int) {
 	// Create that pool
-	pool, _ := ants.NewPool(number)
-	Pool = pool
+	q, err := New(number)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	Default = q
+	Pool = q.Pool
+}
+
+// Dispatch runs the handler in a goroutine of the queue.
+// It returns ErrorClosed once the queue is waiting for its jobs.
+func (q *Queue) Dispatch(handler func()) error {
+	q.mutex.Lock()
+	if q.closed {
+		q.mutex.Unlock()
+		return ErrorClosed
+	}
+	q.pending.Add(1)
+	q.mutex.Unlock()
+	err := q.Pool.Submit(func() {
+		defer q.pending.Done()
+		handler()
+	})
+	if err != nil {
+		q.pending.Done()
+	}
+	return err
 }
 
-// Dispatch runs the handler in a goroutine
+// Wait closes the queue, rejecting the jobs dispatched from now on, and blocks
+// until all the dispatched jobs have finished or the given context is done,
+// whichever happens first.
+func (q *Queue) Wait(ctx context.Context) error {
+	q.mutex.Lock()
+	q.closed = true
+	q.mutex.Unlock()
+	done := make(chan struct{})
+	go func() {
+		q.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release closes the queue pool.
+func (q *Queue) Release() error {
+	return q.Pool.Release()
+}
+
+// Dispatch runs the handler in a goroutine of the default queue.
 func Dispatch(handler func()) error {
-	return Pool.Submit(handler)
+	return Default.Dispatch(handler)
+}
+
+// Wait blocks until all the jobs of the default queue have finished
+// or the given context is done, whichever happens first.
+func Wait(ctx context.Context) error {
+	return Default.Wait(ctx)
+}
+
+// Resize changes the number of routines of the queue.
+func (q *Queue) Resize(number int) {
+	q.Pool.Tune(number)
 }
@@ -0,0 +1,52 @@
+package queue
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWait(t *testing.T) {
+	q, err := New(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Release()
+	var finished int32
+	for i := 0; i < 5; i++ {
+		if err := q.Dispatch(func() {
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&finished, 1)
+		}); err != nil {
+			t.Fatalf("Dispatch() error = %v", err)
+		}
+	}
+	if err := q.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if n := atomic.LoadInt32(&finished); n != 5 {
+		t.Errorf("Wait() returned with %d finished jobs, want 5", n)
+	}
+	if err := q.Dispatch(func() {}); err != ErrorClosed {
+		t.Errorf("Dispatch() after Wait() error = %v, want %v", err, ErrorClosed)
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	q, err := New(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Release()
+	release := make(chan struct{})
+	defer close(release)
+	if err := q.Dispatch(func() { <-release }); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,140 @@
+package request
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Accepted represents a value of an Accept header with its quality.
+type Accepted struct {
+	Value   string
+	Quality float64
+}
+
+// parseAccept parses the Accept header values, sorted by quality
+// and skipping the ones with a quality of zero.
+func parseAccept(header string) []Accepted {
+	var list []Accepted
+	for _, accepted := range parseValues(header) {
+		if accepted.Quality > 0 {
+			list = append(list, accepted)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Quality > list[j].Quality
+	})
+	return list
+}
+
+// parseValues parses the Accept header values in order.
+func parseValues(header string) []Accepted {
+	var list []Accepted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		value := strings.ToLower(strings.TrimSpace(fields[0]))
+		if value == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		list = append(list, Accepted{Value: value, Quality: quality})
+	}
+	return list
+}
+
+// Accepts returns the media types of the Accept header, sorted by quality.
+func (req *HTTP) Accepts() []Accepted {
+	return parseAccept(req.Request.Header.Get("Accept"))
+}
+
+// AcceptsLanguages returns the languages of the Accept-Language header, sorted by quality.
+func (req *HTTP) AcceptsLanguages() []Accepted {
+	return parseAccept(req.Request.Header.Get("Accept-Language"))
+}
+
+// AcceptsEncodings returns the encodings of the Accept-Encoding header, sorted by quality.
+func (req *HTTP) AcceptsEncodings() []Accepted {
+	return parseAccept(req.Request.Header.Get("Accept-Encoding"))
+}
+
+// Prefers returns the media type the client prefers from the offers, in
+// order of preference when tied, or an empty string if none is acceptable.
+func (req *HTTP) Prefers(offers ...string) string {
+	return prefer(req.Request.Header.Get("Accept"), offers, func(accepted, offer string) int {
+		switch {
+		case accepted == offer:
+			return 3
+		case strings.HasSuffix(accepted, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(accepted, "*")):
+			return 2
+		case accepted == "*/*" || accepted == "*":
+			return 1
+		}
+		return 0
+	})
+}
+
+// PrefersLanguage returns the language the client prefers from the offers,
+// in order of preference when tied, or an empty string if none is acceptable.
+// A language like "en" also matches the offers "en-US" and "en-GB".
+func (req *HTTP) PrefersLanguage(offers ...string) string {
+	return prefer(req.Request.Header.Get("Accept-Language"), offers, func(accepted, offer string) int {
+		switch {
+		case accepted == offer:
+			return 3
+		case strings.HasPrefix(offer, accepted+"-"):
+			return 2
+		case accepted == "*":
+			return 1
+		}
+		return 0
+	})
+}
+
+// PrefersEncoding returns the encoding the client prefers from the offers, in
+// order of preference when tied, or an empty string if none is acceptable.
+func (req *HTTP) PrefersEncoding(offers ...string) string {
+	return prefer(req.Request.Header.Get("Accept-Encoding"), offers, func(accepted, offer string) int {
+		switch {
+		case accepted == offer:
+			return 2
+		case accepted == "*":
+			return 1
+		}
+		return 0
+	})
+}
+
+// prefer returns the offer with the highest quality, using the quality of the
+// most specific accepted value that matches it. Every offer is acceptable
+// when the header is missing.
+func prefer(header string, offers []string, match func(accepted, offer string) int) string {
+	if strings.TrimSpace(header) == "" {
+		if len(offers) > 0 {
+			return offers[0]
+		}
+		return ""
+	}
+	// Values with a quality of zero are kept so they can reject offers.
+	list := parseValues(header)
+	best, bestQuality := "", 0.0
+	for _, offer := range offers {
+		specificity, quality := 0, 0.0
+		for _, accepted := range list {
+			if s := match(accepted.Value, strings.ToLower(offer)); s > specificity {
+				specificity, quality = s, accepted.Quality
+			}
+		}
+		if quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+	return best
+}
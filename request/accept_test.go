@@ -0,0 +1,166 @@
+package request_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pulsar-go/pulsar/config"
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+	"github.com/pulsar-go/pulsar/router"
+)
+
+// accepting returns a request with the header.
+func accepting(header, value string) *request.HTTP {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if value != "" {
+		r.Header.Set(header, value)
+	}
+	return &request.HTTP{Request: r}
+}
+
+func TestPrefers(t *testing.T) {
+	offers := []string{"application/json", "application/xml", "text/html"}
+	tests := []struct {
+		accept string
+		offers []string
+		want   string
+	}{
+		{"", offers, "application/json"},
+		{"*/*", offers, "application/json"},
+		{"text/html", offers, "text/html"},
+		{"text/html, application/json;q=0.9", offers, "text/html"},
+		{"text/html;q=0.5, application/xml", offers, "application/xml"},
+		{"application/*", offers, "application/json"},
+		{"application/*;q=0.5, application/xml", offers, "application/xml"},
+		{"text/*, */*;q=0.1", offers, "text/html"},
+		{"*/*, application/json;q=0", offers, "application/xml"},
+		{"image/png", offers, ""},
+		{"TEXT/HTML", offers, "text/html"},
+		{"", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := accepting("Accept", tt.accept).Prefers(tt.offers...); got != tt.want {
+			t.Errorf("Prefers(%v) with Accept %q = %q, want %q", tt.offers, tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestPrefersLanguage(t *testing.T) {
+	offers := []string{"en-US", "es", "ca"}
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", "en-US"},
+		{"ca", "ca"},
+		{"es-ES, ca;q=0.8", "ca"},
+		{"en", "en-US"},
+		{"fr, *;q=0.5", "en-US"},
+		{"es;q=0.4, ca;q=0.6", "ca"},
+		{"fr", ""},
+	}
+	for _, tt := range tests {
+		if got := accepting("Accept-Language", tt.accept).PrefersLanguage(offers...); got != tt.want {
+			t.Errorf("PrefersLanguage() with Accept-Language %q = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestPrefersEncoding(t *testing.T) {
+	offers := []string{"br", "gzip", "identity"}
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", "br"},
+		{"gzip, deflate", "gzip"},
+		{"gzip;q=0.5, br", "br"},
+		{"*;q=0.1, gzip", "gzip"},
+		{"deflate", ""},
+	}
+	for _, tt := range tests {
+		if got := accepting("Accept-Encoding", tt.accept).PrefersEncoding(offers...); got != tt.want {
+			t.Errorf("PrefersEncoding() with Accept-Encoding %q = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestAccepts(t *testing.T) {
+	accepted := accepting("Accept", "text/html;q=0.5, application/json, image/png;q=0, */*;q=0.1").Accepts()
+	var values []string
+	for _, a := range accepted {
+		values = append(values, a.Value)
+	}
+	if got := strings.Join(values, ","); got != "application/json,text/html,*/*" {
+		t.Errorf("Accepts() = %s, want the values by quality without the rejected ones", got)
+	}
+}
+
+// photo is the data of the negotiated responses.
+type photo struct {
+	Title string `json:"title" xml:"title"`
+}
+
+func TestNegotiate(t *testing.T) {
+	views, err := ioutil.TempDir("", "pulsar-views")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(views)
+	if err := ioutil.WriteFile(filepath.Join(views, "photo.gohtml"), []byte("<h1>{{.Title}}</h1>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := config.Defaults()
+	c.Views.Path = views
+	routes := func(r *router.Router) {
+		r.Get("/photo", func(req *request.HTTP) response.HTTP {
+			return response.NegotiateWithCode(photo{Title: "Sea"}, "photo", http.StatusCreated)
+		})
+		r.Get("/api/photo", func(req *request.HTTP) response.HTTP {
+			return response.Negotiate(photo{Title: "Sea"}, "")
+		})
+		r.Get("/map", func(req *request.HTTP) response.HTTP {
+			return response.Negotiate(map[string]string{"title": "Sea"}, "")
+		})
+	}
+	tests := []struct {
+		target      string
+		accept      string
+		code        int
+		contentType string
+		body        string
+	}{
+		{"/photo", "", http.StatusCreated, "application/json", `{"title":"Sea"}`},
+		{"/photo", "application/xml", http.StatusCreated, "application/xml", "<photo><title>Sea</title></photo>"},
+		{"/photo", "text/xml", http.StatusCreated, "application/xml", "<photo><title>Sea</title></photo>"},
+		{"/photo", "text/html,application/xhtml+xml", http.StatusCreated, "", "<h1>Sea</h1>"},
+		{"/api/photo", "text/html", http.StatusNotAcceptable, "", ""},
+		{"/api/photo", "text/html, application/json;q=0.1", http.StatusOK, "application/json", `{"title":"Sea"}`},
+		// Maps can't be marshaled as XML, so it isn't offered.
+		{"/map", "application/xml", http.StatusNotAcceptable, "", ""},
+		{"/map", "application/xml, */*;q=0.1", http.StatusOK, "application/json", `{"title":"Sea"}`},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		if tt.accept != "" {
+			r.Header.Set("Accept", tt.accept)
+		}
+		w := serve(c, routes, r)
+		if w.Code != tt.code {
+			t.Errorf("GET %s with Accept %q = %d %q, want %d", tt.target, tt.accept, w.Code, w.Body.String(), tt.code)
+			continue
+		}
+		if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, tt.contentType) {
+			t.Errorf("GET %s with Accept %q Content-Type = %q, want %q", tt.target, tt.accept, contentType, tt.contentType)
+		}
+		if body := strings.TrimSpace(w.Body.String()); tt.body != "" && !strings.HasSuffix(body, tt.body) {
+			t.Errorf("GET %s with Accept %q = %q, want %q", tt.target, tt.accept, body, tt.body)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package request_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/pulsar-go/pulsar"
+	"github.com/pulsar-go/pulsar/config"
+	"github.com/pulsar-go/pulsar/router"
+)
+
+// serve sends the request to a new application with the
+// settings and the routes, returning its response.
+func serve(c config.Config, routes func(r *router.Router), r *http.Request) *httptest.ResponseRecorder {
+	app := pulsar.New(&c)
+	routes(app.Router)
+	w := httptest.NewRecorder()
+	app.Handler().ServeHTTP(w, r)
+	return w
+}
+
+// post returns the routes of a POST /echo route using the handler.
+func post(handler router.Handler) func(r *router.Router) {
+	return func(r *router.Router) {
+		r.Post("/echo", handler)
+	}
+}
@@ -0,0 +1,200 @@
+package request
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ContentTypeError determines that the request content type can't be bound.
+type ContentTypeError struct {
+	ContentType string
+}
+
+// Error returns the error message.
+func (e *ContentTypeError) Error() string {
+	return fmt.Sprintf("The content type '%s' is not supported", e.ContentType)
+}
+
+// BindError determines that a request value can't be bound to a field.
+type BindError struct {
+	Field  string
+	Source string
+	Value  string
+	Err    error
+}
+
+// Error returns the error message.
+func (e *BindError) Error() string {
+	return fmt.Sprintf("Unable to bind %s value '%s' to field %s: %s", e.Source, e.Value, e.Field, e.Err)
+}
+
+// MediaType returns the media type of the request Content-Type header.
+func (req *HTTP) MediaType() string {
+	mediaType, _, err := mime.ParseMediaType(req.Request.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+// IsJSON determines if the request has a JSON Content-Type header.
+func (req *HTTP) IsJSON() bool {
+	mediaType := req.MediaType()
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// BindJSON binds the JSON body to the destination, failing with
+// ErrorNoJSONHeader when the request has no JSON Content-Type header.
+func (req *HTTP) BindJSON(dst interface{}) error {
+	if !req.IsJSON() {
+		return ErrorNoJSONHeader
+	}
+	return req.JSON(dst)
+}
+
+// Bind binds the request to the destination, a pointer to a struct. The body
+// is decoded as JSON, urlencoded or multipart form depending on its Content-Type,
+// using the `form` tags for the forms. Requests without body bind their query to
+// the `form` tags instead. The `query` tags are bound to the URL query and the
+// `param` tags to the route parameters. Unsupported content types return a
+// *ContentTypeError and invalid values a *BindError.
+func (req *HTTP) Bind(dst interface{}) error {
+	value := reflect.ValueOf(dst)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Bind expects a pointer to a struct, got %T", dst)
+	}
+	target := value.Elem()
+	query := req.Request.URL.Query()
+	if hasBody(req.Request) {
+		switch mediaType := req.MediaType(); {
+		case req.IsJSON():
+			if err := req.JSON(dst); err != nil {
+				return err
+			}
+		case mediaType == "application/x-www-form-urlencoded":
+			if err := req.Request.ParseForm(); err != nil {
+				return err
+			}
+			if err := bindValues(target, "form", req.Request.PostForm); err != nil {
+				return err
+			}
+		case mediaType == "multipart/form-data":
+			if err := req.parseMultipart(); err != nil {
+				return err
+			}
+			if err := bindValues(target, "form", req.Request.MultipartForm.Value); err != nil {
+				return err
+			}
+		default:
+			return &ContentTypeError{ContentType: mediaType}
+		}
+	} else if err := bindValues(target, "form", query); err != nil {
+		return err
+	}
+	if err := bindValues(target, "query", query); err != nil {
+		return err
+	}
+	params := make(map[string][]string, len(req.Params))
+	for _, param := range req.Params {
+		params[param.Key] = []string{param.Value}
+	}
+	return bindValues(target, "param", params)
+}
+
+// hasBody determines if the request method carries a body and the request has one.
+func hasBody(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return r.ContentLength != 0 || r.Header.Get("Content-Type") != ""
+	}
+	return false
+}
+
+// bindValues sets the struct fields with the given tag to their values.
+// Untagged embedded structs are bound as well.
+func bindValues(target reflect.Value, tag string, values map[string][]string) error {
+	t := target.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := bindValues(target.Field(i), tag, values); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "" || name == "-" || field.PkgPath != "" {
+			continue
+		}
+		list, ok := values[name]
+		if !ok || len(list) == 0 {
+			continue
+		}
+		if err := setField(target.Field(i), list); err != nil {
+			return &BindError{Field: field.Name, Source: tag, Value: strings.Join(list, ","), Err: err}
+		}
+	}
+	return nil
+}
+
+// setField sets the field to the values, converting them to its type.
+func setField(field reflect.Value, values []string) error {
+	switch field.Kind() {
+	case reflect.Ptr:
+		value := reflect.New(field.Type().Elem())
+		if err := setField(value.Elem(), values); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	case reflect.Slice:
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, v := range values {
+			if err := setValue(slice.Index(i), v); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setValue(field, values[0])
+}
+
+// setValue sets the field to the value, converting it to its type.
+func setValue(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
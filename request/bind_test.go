@@ -0,0 +1,171 @@
+package request_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pulsar-go/pulsar/config"
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+	"github.com/pulsar-go/pulsar/router"
+)
+
+// Pagination is embedded in the bound input.
+type Pagination struct {
+	Page int `query:"page"`
+}
+
+// photoInput is the input bound from the photo requests.
+type photoInput struct {
+	Pagination
+	Title  string   `json:"title" form:"title"`
+	Tags   []string `json:"tags" form:"tags"`
+	Width  int      `json:"width" form:"width"`
+	Public *bool    `json:"public" form:"public"`
+	User   uint     `param:"user"`
+	secret string
+}
+
+// bindRoutes returns the routes responding with the bound photo input as JSON.
+func bindRoutes(r *router.Router) {
+	bind := func(req *request.HTTP) response.HTTP {
+		var input photoInput
+		if err := req.Bind(&input); err != nil {
+			return response.Invalid(req, err)
+		}
+		return response.JSON(input)
+	}
+	r.Get("/users/:user/photos", bind)
+	r.Post("/users/:user/photos", bind)
+}
+
+// multipartBody returns a multipart form body with the values and its content type.
+func multipartBody(values map[string][]string) (io.Reader, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, list := range values {
+		for _, value := range list {
+			writer.WriteField(name, value)
+		}
+	}
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func TestBind(t *testing.T) {
+	public := true
+	form, formType := multipartBody(map[string][]string{"title": {"Sea"}, "tags": {"a", "b"}, "width": {"5"}})
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        io.Reader
+		code        int
+		input       photoInput
+	}{
+		{
+			name:        "json",
+			method:      http.MethodPost,
+			target:      "/users/7/photos?page=2",
+			contentType: "application/json",
+			body:        strings.NewReader(`{"title":"Sea","tags":["a","b"],"width":5,"public":true}`),
+			code:        http.StatusOK,
+			input:       photoInput{Pagination{2}, "Sea", []string{"a", "b"}, 5, &public, 7, ""},
+		},
+		{
+			name:        "urlencoded",
+			method:      http.MethodPost,
+			target:      "/users/7/photos",
+			contentType: "application/x-www-form-urlencoded; charset=utf-8",
+			body:        strings.NewReader("title=Sea&tags=a&tags=b&width=5&public=true"),
+			code:        http.StatusOK,
+			input:       photoInput{Pagination{}, "Sea", []string{"a", "b"}, 5, &public, 7, ""},
+		},
+		{
+			name:        "multipart",
+			method:      http.MethodPost,
+			target:      "/users/7/photos?page=3",
+			contentType: formType,
+			body:        form,
+			code:        http.StatusOK,
+			input:       photoInput{Pagination{3}, "Sea", []string{"a", "b"}, 5, nil, 7, ""},
+		},
+		{
+			name:   "query without body",
+			method: http.MethodGet,
+			target: "/users/7/photos?title=Sea&tags=a&page=4",
+			code:   http.StatusOK,
+			input:  photoInput{Pagination{4}, "Sea", []string{"a"}, 0, nil, 7, ""},
+		},
+		{
+			name:        "unsupported content type",
+			method:      http.MethodPost,
+			target:      "/users/7/photos",
+			contentType: "text/plain",
+			body:        strings.NewReader("Sea"),
+			code:        http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "invalid value",
+			method:      http.MethodPost,
+			target:      "/users/7/photos",
+			contentType: "application/x-www-form-urlencoded",
+			body:        strings.NewReader("width=wide"),
+			code:        http.StatusBadRequest,
+		},
+		{
+			name:   "invalid query value",
+			method: http.MethodGet,
+			target: "/users/7/photos?page=first",
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:        "invalid json",
+			method:      http.MethodPost,
+			target:      "/users/7/photos",
+			contentType: "application/json",
+			body:        strings.NewReader(`{"title":`),
+			code:        http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, tt.body)
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			w := serve(config.Defaults(), bindRoutes, r)
+			if w.Code != tt.code {
+				t.Fatalf("%s %s = %d %q, want %d", tt.method, tt.target, w.Code, w.Body.String(), tt.code)
+			}
+			if tt.code != http.StatusOK {
+				return
+			}
+			var input photoInput
+			if err := json.Unmarshal(w.Body.Bytes(), &input); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(input, tt.input) {
+				t.Errorf("Bind() = %+v, want %+v", input, tt.input)
+			}
+		})
+	}
+}
+
+func TestBindDestination(t *testing.T) {
+	req := &request.HTTP{Request: httptest.NewRequest(http.MethodGet, "/", nil)}
+	var input photoInput
+	for _, dst := range []interface{}{input, &input.Title, nil} {
+		if err := req.Bind(dst); err == nil {
+			t.Errorf("Bind(%T) error = nil, want an error", dst)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package request
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// ErrorBodyTooLarge determines that the request body exceeds the maximum size.
+var ErrorBodyTooLarge = errors.New("The request body is too large")
+
+// countedBody counts the bytes read from the original request body.
+type countedBody struct {
+	io.ReadCloser
+	read int64
+}
+
+// Read reads from the body, counting the bytes read.
+func (c *countedBody) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.read += int64(n)
+	return n, err
+}
+
+// limitedBody limits the request body with http.MaxBytesReader, so the server
+// closes the connection of the bodies over the limit, failing their reads
+// with ErrorBodyTooLarge.
+type limitedBody struct {
+	io.ReadCloser
+	// counted is the body under the MaxBytesReader, which reads
+	// past the limit only when the body exceeds it.
+	counted *countedBody
+	limit   int64
+}
+
+// Read reads from the body until the limit is exceeded.
+func (l *limitedBody) Read(p []byte) (int, error) {
+	n, err := l.ReadCloser.Read(p)
+	if err != nil && l.exceeded() {
+		err = ErrorBodyTooLarge
+	}
+	return n, err
+}
+
+// exceeded determines if the body has more bytes than the limit.
+func (l *limitedBody) exceeded() bool {
+	return l.counted.read > l.limit
+}
+
+// LimitBody makes the reads of the request body fail with
+// ErrorBodyTooLarge past the limit. A limit of zero means no limit.
+func (req *HTTP) LimitBody(limit int64) {
+	if limit > 0 && req.Request.Body != nil {
+		counted := &countedBody{ReadCloser: req.Request.Body}
+		req.limited = &limitedBody{
+			ReadCloser: http.MaxBytesReader(req.Writer, counted, limit),
+			counted:    counted,
+			limit:      limit,
+		}
+		req.Request.Body = req.limited
+	}
+}
+
+// BodyTooLarge determines if a read of the request body exceeded the limit,
+// even when the ErrorBodyTooLarge error was wrapped by the body parsers.
+func (req *HTTP) BodyTooLarge() bool {
+	return req.limited != nil && req.limited.exceeded()
+}
+
+// ReadBody reads the whole request body, buffering it in
+// Body so it can be read again.
+func (req *HTTP) ReadBody() ([]byte, error) {
+	if req.buffered {
+		return []byte(req.Body), nil
+	}
+	if req.Request.Body == nil {
+		req.buffered = true
+		return nil, nil
+	}
+	buff, err := ioutil.ReadAll(req.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = string(buff)
+	req.Request.Body = ioutil.NopCloser(bytes.NewBuffer(buff))
+	req.buffered = true
+	return buff, nil
+}
@@ -0,0 +1,113 @@
+package request_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pulsar-go/pulsar"
+	"github.com/pulsar-go/pulsar/config"
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+)
+
+// echo responds with the request body, or with the error reading it.
+func echo(req *request.HTTP) response.HTTP {
+	body, err := ioutil.ReadAll(req.Request.Body)
+	if err != nil {
+		return response.Invalid(req, err)
+	}
+	return response.Text(string(body))
+}
+
+// readBody responds with the body read by ReadBody, or with the error reading it.
+func readBody(req *request.HTTP) response.HTTP {
+	body, err := req.ReadBody()
+	if err != nil {
+		return response.Invalid(req, err)
+	}
+	return response.Text(string(body))
+}
+
+func TestBodyLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		buffer   bool
+		handler  func(req *request.HTTP) response.HTTP
+		body     string
+		streamed bool
+		code     int
+	}{
+		{"within the limit", false, echo, "0123456789", false, http.StatusOK},
+		{"over the limit", false, echo, "0123456789a", false, http.StatusRequestEntityTooLarge},
+		{"streamed within the limit", false, echo, "0123456789", true, http.StatusOK},
+		{"streamed over the limit", false, echo, "0123456789a", true, http.StatusRequestEntityTooLarge},
+		{"read body over the limit", false, readBody, "0123456789a", true, http.StatusRequestEntityTooLarge},
+		{"buffered within the limit", true, echo, "0123456789", true, http.StatusOK},
+		{"buffered over the limit", true, echo, "0123456789a", true, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := config.Defaults()
+			c.Server.MaxBodySize = "10B"
+			c.Server.BufferBody = tt.buffer
+			r := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(tt.body))
+			if tt.streamed {
+				r.ContentLength = -1
+			}
+			w := serve(c, post(tt.handler), r)
+			if w.Code != tt.code {
+				t.Fatalf("POST /echo = %d %q, want %d", w.Code, w.Body.String(), tt.code)
+			}
+			if tt.code == http.StatusOK && w.Body.String() != tt.body {
+				t.Errorf("POST /echo = %q, want %q", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestBodyTooLarge(t *testing.T) {
+	tests := []struct {
+		body string
+		want bool
+	}{
+		{"", false},
+		{"0123456789", false},
+		{"0123456789a", true},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+		req := &request.HTTP{Request: r, Writer: httptest.NewRecorder()}
+		req.LimitBody(10)
+		body, err := ioutil.ReadAll(req.Request.Body)
+		if tt.want && err != request.ErrorBodyTooLarge || !tt.want && err != nil {
+			t.Errorf("ReadAll(%q) error = %v, want too large %v", tt.body, err, tt.want)
+		}
+		if len(body) > 10 {
+			t.Errorf("ReadAll(%q) read %d bytes, want at most the limit", tt.body, len(body))
+		}
+		if got := req.BodyTooLarge(); got != tt.want {
+			t.Errorf("BodyTooLarge() with %q = %v, want %v", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestBodyLimitClosesConnection(t *testing.T) {
+	c := config.Defaults()
+	c.Server.MaxBodySize = "10B"
+	app := pulsar.New(&c)
+	app.Router.Post("/echo", echo)
+	server := httptest.NewServer(app.Handler())
+	defer server.Close()
+	// The reader hides the length, so the body is sent chunked.
+	res, err := http.Post(server.URL+"/echo", "text/plain", ioutil.NopCloser(strings.NewReader(strings.Repeat("a", 100))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusRequestEntityTooLarge || !res.Close {
+		t.Errorf("POST /echo = %d closing %v, want %d closing the connection", res.StatusCode, res.Close, http.StatusRequestEntityTooLarge)
+	}
+}
@@ -0,0 +1,123 @@
+package request
+
+import (
+	"context"
+	"time"
+)
+
+// Key is a key of a request value. Keys are compared by identity, so two
+// keys created with the same name never collide.
+type Key struct {
+	name string
+}
+
+// NewKey creates a new request value key. The name is only descriptive.
+func NewKey(name string) *Key {
+	return &Key{name: name}
+}
+
+// String returns the name of the key.
+func (k *Key) String() string {
+	return k.name
+}
+
+// Context returns the request context, which is canceled when the
+// client disconnects or when its deadline, if any, is exceeded.
+func (req *HTTP) Context() context.Context {
+	return req.Request.Context()
+}
+
+// WithContext replaces the request context.
+func (req *HTTP) WithContext(ctx context.Context) {
+	req.Request = req.Request.WithContext(ctx)
+}
+
+// WithTimeout makes the request context expire after the timeout.
+// The returned function releases its resources and should be deferred.
+func (req *HTTP) WithTimeout(timeout time.Duration) context.CancelFunc {
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	req.WithContext(ctx)
+	return cancel
+}
+
+// WithDeadline makes the request context expire at the deadline.
+// The returned function releases its resources and should be deferred.
+func (req *HTTP) WithDeadline(deadline time.Time) context.CancelFunc {
+	ctx, cancel := context.WithDeadline(req.Context(), deadline)
+	req.WithContext(ctx)
+	return cancel
+}
+
+// Set stores a value in the request context.
+func (req *HTTP) Set(key *Key, value interface{}) {
+	req.WithContext(context.WithValue(req.Context(), key, value))
+}
+
+// Get returns a value stored in the request context.
+func (req *HTTP) Get(key *Key) (interface{}, bool) {
+	value := req.Context().Value(key)
+	return value, value != nil
+}
+
+// StringKey is a key of a string request value.
+type StringKey struct {
+	key *Key
+}
+
+// NewStringKey creates a new key of a string request value.
+func NewStringKey(name string) StringKey {
+	return StringKey{key: NewKey(name)}
+}
+
+// Set stores the value in the request.
+func (k StringKey) Set(req *HTTP, value string) {
+	req.Set(k.key, value)
+}
+
+// Get returns the value stored in the request.
+func (k StringKey) Get(req *HTTP) (string, bool) {
+	value, ok := req.Context().Value(k.key).(string)
+	return value, ok
+}
+
+// IntKey is a key of an int request value.
+type IntKey struct {
+	key *Key
+}
+
+// NewIntKey creates a new key of an int request value.
+func NewIntKey(name string) IntKey {
+	return IntKey{key: NewKey(name)}
+}
+
+// Set stores the value in the request.
+func (k IntKey) Set(req *HTTP, value int) {
+	req.Set(k.key, value)
+}
+
+// Get returns the value stored in the request.
+func (k IntKey) Get(req *HTTP) (int, bool) {
+	value, ok := req.Context().Value(k.key).(int)
+	return value, ok
+}
+
+// BoolKey is a key of a bool request value.
+type BoolKey struct {
+	key *Key
+}
+
+// NewBoolKey creates a new key of a bool request value.
+func NewBoolKey(name string) BoolKey {
+	return BoolKey{key: NewKey(name)}
+}
+
+// Set stores the value in the request.
+func (k BoolKey) Set(req *HTTP, value bool) {
+	req.Set(k.key, value)
+}
+
+// Get returns the value stored in the request.
+func (k BoolKey) Get(req *HTTP) (bool, bool) {
+	value, ok := req.Context().Value(k.key).(bool)
+	return value, ok
+}
@@ -0,0 +1,35 @@
+package request
+
+import "github.com/pulsar-go/pulsar/cookie"
+
+// Cookie returns the value of a plain cookie.
+func (req *HTTP) Cookie(name string) (string, error) {
+	return req.cookie(name, cookie.Plain)
+}
+
+// SignedCookie returns the value of a signed cookie, failing
+// with cookie.ErrorInvalid when it was modified.
+func (req *HTTP) SignedCookie(name string) (string, error) {
+	return req.cookie(name, cookie.Signed)
+}
+
+// EncryptedCookie returns the value of an encrypted cookie, failing
+// with cookie.ErrorInvalid when it was modified.
+func (req *HTTP) EncryptedCookie(name string) (string, error) {
+	return req.cookie(name, cookie.Encrypted)
+}
+
+// cookie returns the value of the cookie protected with the mode.
+func (req *HTTP) cookie(name string, mode cookie.Mode) (string, error) {
+	c, err := req.Request.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	if mode == cookie.Plain {
+		return c.Value, nil
+	}
+	if req.Cookies == nil {
+		return "", cookie.ErrorNoKey
+	}
+	return req.Cookies.Decode(name, c.Value, mode)
+}
@@ -0,0 +1,90 @@
+package request
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// uuidPattern matches a UUID in its canonical form.
+var uuidPattern = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+
+// IsUUID determines if the value is a UUID.
+func IsUUID(value string) bool {
+	return uuidPattern.MatchString(value)
+}
+
+// ParamError determines that a route parameter is missing or has an invalid value.
+type ParamError struct {
+	Name  string
+	Value string
+	Type  string
+}
+
+// Error returns the error message.
+func (e *ParamError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("Route parameter '%s' is missing", e.Name)
+	}
+	return fmt.Sprintf("Route parameter '%s' must be %s, got '%s'", e.Name, e.Type, e.Value)
+}
+
+// param returns the value of a non empty route parameter.
+func (req *HTTP) param(name string, kind string) (string, error) {
+	value := req.Params.ByName(name)
+	if value == "" {
+		return "", &ParamError{Name: name, Type: kind}
+	}
+	return value, nil
+}
+
+// ParamInt returns the route parameter as an int.
+func (req *HTTP) ParamInt(name string) (int, error) {
+	value, err := req.param(name, "an integer")
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &ParamError{Name: name, Value: value, Type: "an integer"}
+	}
+	return i, nil
+}
+
+// ParamUint returns the route parameter as an uint.
+func (req *HTTP) ParamUint(name string) (uint, error) {
+	value, err := req.param(name, "an unsigned integer")
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.ParseUint(value, 10, 0)
+	if err != nil {
+		return 0, &ParamError{Name: name, Value: value, Type: "an unsigned integer"}
+	}
+	return uint(i), nil
+}
+
+// ParamFloat returns the route parameter as a float64.
+func (req *HTTP) ParamFloat(name string) (float64, error) {
+	value, err := req.param(name, "a number")
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, &ParamError{Name: name, Value: value, Type: "a number"}
+	}
+	return f, nil
+}
+
+// ParamUUID returns the route parameter if it's a UUID.
+func (req *HTTP) ParamUUID(name string) (string, error) {
+	value, err := req.param(name, "a UUID")
+	if err != nil {
+		return "", err
+	}
+	if !IsUUID(value) {
+		return "", &ParamError{Name: name, Value: value, Type: "a UUID"}
+	}
+	return value, nil
+}
@@ -0,0 +1,67 @@
+package request_test
+
+import (
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pulsar-go/pulsar/request"
+)
+
+func TestParams(t *testing.T) {
+	req := &request.HTTP{Params: httprouter.Params{
+		{Key: "int", Value: "-42"},
+		{Key: "uint", Value: "42"},
+		{Key: "float", Value: "4.2"},
+		{Key: "uuid", Value: "123e4567-e89b-12d3-a456-426614174000"},
+		{Key: "word", Value: "pulsar"},
+	}}
+	tests := []struct {
+		name  string
+		get   func(name string) (interface{}, error)
+		param string
+		want  interface{}
+		err   string
+	}{
+		{"int", paramInt(req), "int", -42, ""},
+		{"int invalid", paramInt(req), "float", 0, "Route parameter 'float' must be an integer, got '4.2'"},
+		{"int missing", paramInt(req), "missing", 0, "Route parameter 'missing' is missing"},
+		{"uint", paramUint(req), "uint", uint(42), ""},
+		{"uint negative", paramUint(req), "int", uint(0), "Route parameter 'int' must be an unsigned integer, got '-42'"},
+		{"float", paramFloat(req), "float", 4.2, ""},
+		{"float integer", paramFloat(req), "int", -42.0, ""},
+		{"float invalid", paramFloat(req), "word", 0.0, "Route parameter 'word' must be a number, got 'pulsar'"},
+		{"uuid", paramUUID(req), "uuid", "123e4567-e89b-12d3-a456-426614174000", ""},
+		{"uuid invalid", paramUUID(req), "word", "", "Route parameter 'word' must be a UUID, got 'pulsar'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get(tt.param)
+			if tt.err != "" {
+				if _, ok := err.(*request.ParamError); !ok || err.Error() != tt.err {
+					t.Fatalf("error = %v, want the ParamError %q", err, tt.err)
+				}
+			} else if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("value = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func paramInt(req *request.HTTP) func(string) (interface{}, error) {
+	return func(name string) (interface{}, error) { return req.ParamInt(name) }
+}
+
+func paramUint(req *request.HTTP) func(string) (interface{}, error) {
+	return func(name string) (interface{}, error) { return req.ParamUint(name) }
+}
+
+func paramFloat(req *request.HTTP) func(string) (interface{}, error) {
+	return func(name string) (interface{}, error) { return req.ParamFloat(name) }
+}
+
+func paramUUID(req *request.HTTP) func(string) (interface{}, error) {
+	return func(name string) (interface{}, error) { return req.ParamUUID(name) }
+}
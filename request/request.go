@@ -7,10 +7,13 @@ import (
 	"net/http"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/pulsar-go/pulsar/config"
+	"github.com/pulsar-go/pulsar/cookie"
+	"github.com/pulsar-go/pulsar/db"
 )
 
 // ErrorNoJSONHeader determines that the current request have no JSON headers.
-var ErrorNoJSONHeader = errors.New("a")
+var ErrorNoJSONHeader = errors.New("The request has no JSON Content-Type header")
 
 // Type is the name of the response type.
 type Type uint
@@ -23,18 +26,86 @@ const (
 	PutRequest
 	PatchRequest
 	DeleteRequest
+	OptionsRequest
+	// CustomRequest is used for any other HTTP method, like WebDAV ones.
+	CustomRequest
 )
 
+// methods are the HTTP methods of the request types.
+var methods = map[Type]string{
+	GetRequest:     http.MethodGet,
+	HeadRequest:    http.MethodHead,
+	PostRequest:    http.MethodPost,
+	PutRequest:     http.MethodPut,
+	PatchRequest:   http.MethodPatch,
+	DeleteRequest:  http.MethodDelete,
+	OptionsRequest: http.MethodOptions,
+}
+
+// String returns the HTTP method of the request type.
+func (t Type) String() string {
+	return methods[t]
+}
+
+// TypeOf returns the request type of the given HTTP method.
+// Unknown methods are a CustomRequest.
+func TypeOf(method string) Type {
+	for t, m := range methods {
+		if m == method {
+			return t
+		}
+	}
+	return CustomRequest
+}
+
 // HTTP represents the web server request.
 type HTTP struct {
-	Request     *http.Request
-	Body        string
-	Writer      http.ResponseWriter
-	Params      httprouter.Params
+	Request *http.Request
+	// Body is the request body, only set once it's buffered by ReadBody
+	// or when the server buffers every request body.
+	Body   string
+	Writer http.ResponseWriter
+	Params httprouter.Params
+	// Deprecated: Additionals is nil unless initialized, store
+	// the values in the request context with Set and Get instead.
 	Additionals map[string]interface{}
+	// Models stores the models bound to the route parameters.
+	Models map[string]interface{}
+	// DomainParams stores the placeholders captured from the host.
+	DomainParams httprouter.Params
+	// Cookies reads the signed and encrypted cookies.
+	Cookies *cookie.Jar
+	// Uploads determines how the uploaded files are parsed and stored.
+	Uploads Uploads
+	// Config are the settings of the application serving the
+	// request, which must not be modified.
+	Config *config.Config
+	// DB is the database of the application, db.Builder when nil.
+	DB *db.DB
+	// URL builds the URL of a named route of the application.
+	URL      func(name string, params ...interface{}) (string, error)
+	buffered bool
+	limited  *limitedBody
+}
+
+// Model returns the model bound to the route parameter, or nil if there's none.
+func (req *HTTP) Model(param string) interface{} {
+	return req.Models[param]
 }
 
 // JSON transforms the input body that's formatted in
 func (req *HTTP) JSON(data interface{}) error {
-	return json.NewDecoder(bytes.NewBufferString(req.Body)).Decode(data)
+	buff, err := req.ReadBody()
+	if err != nil {
+		return err
+	}
+	return json.NewDecoder(bytes.NewBuffer(buff)).Decode(data)
+}
+
+// database returns the database of the application.
+func (req *HTTP) database() *db.DB {
+	if req.DB != nil {
+		return req.DB
+	}
+	return db.Builder
 }
@@ -0,0 +1,193 @@
+package request
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrorNoFile determines that no file was uploaded in the field.
+var ErrorNoFile = errors.New("No file was uploaded")
+
+// defaultMaxMemory is the memory used to parse multipart
+// forms when no uploads settings are given.
+const defaultMaxMemory = 32 << 20
+
+// Uploads determines how the uploaded files are parsed and stored.
+type Uploads struct {
+	// Dir is the default directory where the files are stored.
+	Dir string
+	// MaxMemory is the size of the multipart form kept in
+	// memory, the rest is stored in temporary files.
+	MaxMemory int64
+	// MaxFileSize is the default maximum size of a file, zero means no limit.
+	MaxFileSize int64
+}
+
+// File represents an uploaded file.
+type File struct {
+	*multipart.FileHeader
+	Field   string
+	uploads Uploads
+}
+
+// FileError determines that an uploaded file is not valid.
+type FileError struct {
+	Field    string
+	Filename string
+	Message  string
+}
+
+// Error returns the error message.
+func (e *FileError) Error() string {
+	return fmt.Sprintf("File '%s' of field '%s' %s", e.Filename, e.Field, e.Message)
+}
+
+// parseMultipart parses the multipart form of the request once.
+func (req *HTTP) parseMultipart() error {
+	if req.Request.MultipartForm != nil {
+		return nil
+	}
+	memory := req.Uploads.MaxMemory
+	if memory <= 0 {
+		memory = defaultMaxMemory
+	}
+	return req.Request.ParseMultipartForm(memory)
+}
+
+// Files returns all the uploaded files.
+func (req *HTTP) Files() ([]*File, error) {
+	if err := req.parseMultipart(); err != nil {
+		return nil, err
+	}
+	var files []*File
+	for field, headers := range req.Request.MultipartForm.File {
+		for _, header := range headers {
+			files = append(files, &File{FileHeader: header, Field: field, uploads: req.Uploads})
+		}
+	}
+	return files, nil
+}
+
+// FilesOf returns the files uploaded in the field.
+func (req *HTTP) FilesOf(field string) ([]*File, error) {
+	if err := req.parseMultipart(); err != nil {
+		return nil, err
+	}
+	var files []*File
+	for _, header := range req.Request.MultipartForm.File[field] {
+		files = append(files, &File{FileHeader: header, Field: field, uploads: req.Uploads})
+	}
+	return files, nil
+}
+
+// File returns the first file uploaded in the field, or ErrorNoFile.
+func (req *HTTP) File(field string) (*File, error) {
+	files, err := req.FilesOf(field)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, ErrorNoFile
+	}
+	return files[0], nil
+}
+
+// MIME returns the media type of the file sniffed from its content,
+// ignoring the content type sent by the client.
+func (f *File) MIME() (string, error) {
+	file, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	buff := make([]byte, 512)
+	n, err := io.ReadFull(file, buff)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(buff[:n]))
+	if err != nil {
+		return "", err
+	}
+	return mediaType, nil
+}
+
+// Validate checks the file size doesn't exceed the maximum size and its sniffed
+// media type is one of the given ones, which accept wildcards like "image/*".
+// A maximum size of zero uses the configured one, and no types allow any type.
+func (f *File) Validate(maxSize int64, types ...string) error {
+	if maxSize <= 0 {
+		maxSize = f.uploads.MaxFileSize
+	}
+	if maxSize > 0 && f.Size > maxSize {
+		return &FileError{Field: f.Field, Filename: f.Filename, Message: fmt.Sprintf("exceeds the maximum size of %d bytes", maxSize)}
+	}
+	if len(types) == 0 {
+		return nil
+	}
+	mediaType, err := f.MIME()
+	if err != nil {
+		return err
+	}
+	for _, t := range types {
+		if t == mediaType || (strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(t, "*"))) {
+			return nil
+		}
+	}
+	return &FileError{Field: f.Field, Filename: f.Filename, Message: fmt.Sprintf("has type %s, expected %s", mediaType, strings.Join(types, ", "))}
+}
+
+// Store stores the file in the directory with a generated name that keeps
+// the original extension, and returns its path. An empty directory uses
+// the configured one.
+func (f *File) Store(dir string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	// Only keep simple extensions like ".png".
+	ext := strings.ToLower(filepath.Ext(filepath.Base(f.Filename)))
+	for _, c := range strings.TrimPrefix(ext, ".") {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			ext = ""
+			break
+		}
+	}
+	return f.StoreAs(dir, hex.EncodeToString(random)+ext)
+}
+
+// StoreAs stores the file in the directory with the given name, and returns
+// its path. An empty directory uses the configured one.
+func (f *File) StoreAs(dir string, name string) (string, error) {
+	if dir == "" {
+		dir = f.uploads.Dir
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	// Only the base name is used so the file can't leave the directory.
+	path := filepath.Join(dir, filepath.Base(filepath.Clean("/"+name)))
+	src, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return "", err
+	}
+	return path, dst.Close()
+}
@@ -0,0 +1,301 @@
+package request
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pulsar-go/pulsar/cookie"
+	"github.com/pulsar-go/pulsar/db"
+)
+
+// ErrorsCookie is the cookie that carries the validation errors to the next request.
+const ErrorsCookie = "pulsar_errors"
+
+// Rules maps field names to their validation rules, like "required|min:3".
+type Rules map[string]string
+
+// Errors is a bag of validation messages keyed by field name.
+type Errors map[string][]string
+
+// Add adds a message to the field.
+func (e Errors) Add(field, message string) {
+	e[field] = append(e[field], message)
+}
+
+// Has determines if the field has messages.
+func (e Errors) Has(field string) bool {
+	return len(e[field]) > 0
+}
+
+// First returns the first message of the field.
+func (e Errors) First(field string) string {
+	if messages := e[field]; len(messages) > 0 {
+		return messages[0]
+	}
+	return ""
+}
+
+// Error returns all the messages, sorted by field.
+func (e Errors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	var messages []string
+	for _, field := range fields {
+		messages = append(messages, e[field]...)
+	}
+	return strings.Join(messages, " ")
+}
+
+// Validate binds the request to the destination and validates it using the
+// `validate` struct tags and the given rules. The rules are separated by "|"
+// and their arguments by ",": required, email, min:n, max:n, in:a,b,c,
+// regex:pattern (must be the last rule), unique:table,column and
+// exists:table,column. Validation failures are returned as Errors.
+func (req *HTTP) Validate(dst interface{}, rules ...Rules) error {
+	if err := req.Bind(dst); err != nil {
+		return err
+	}
+	if errs := check(req.database(), dst, rules); errs != nil {
+		return errs
+	}
+	return nil
+}
+
+// Check validates the struct the destination points to using the
+// `validate` struct tags and the given rules, which are keyed by the field
+// name used in the bag. It returns nil when there are no errors.
+func Check(dst interface{}, rules ...Rules) Errors {
+	return check(db.Builder, dst, rules)
+}
+
+// check validates the destination using the database for the unique and exists rules.
+func check(database *db.DB, dst interface{}, rules []Rules) Errors {
+	errs := make(Errors)
+	value := reflect.Indirect(reflect.ValueOf(dst))
+	if value.Kind() != reflect.Struct {
+		errs.Add("", fmt.Sprintf("Unable to validate %T", dst))
+		return errs
+	}
+	checkStruct(database, value, rules, errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// checkStruct validates the fields of the struct.
+func checkStruct(database *db.DB, value reflect.Value, rules []Rules, errs Errors) {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			checkStruct(database, value.Field(i), rules, errs)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		name := fieldName(field)
+		list := field.Tag.Get("validate")
+		for _, r := range rules {
+			if extra, ok := r[name]; ok {
+				list = strings.Trim(list+"|"+extra, "|")
+			}
+		}
+		if list != "" {
+			checkField(database, name, value.Field(i), list, errs)
+		}
+	}
+}
+
+// fieldName returns the name of the field used in the bag.
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "query", "param"} {
+		if name := strings.Split(field.Tag.Get(tag), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// checkField validates the field value with the rules.
+func checkField(database *db.DB, name string, value reflect.Value, rules string, errs Errors) {
+	for rules != "" {
+		var rule string
+		if strings.HasPrefix(rules, "regex:") {
+			rule, rules = rules, ""
+		} else {
+			parts := strings.SplitN(rules, "|", 2)
+			rule, rules = parts[0], ""
+			if len(parts) == 2 {
+				rules = parts[1]
+			}
+		}
+		parts := strings.SplitN(rule, ":", 2)
+		arg := ""
+		if len(parts) == 2 {
+			arg = parts[1]
+		}
+		if message := checkRule(database, name, value, parts[0], arg); message != "" {
+			errs.Add(name, message)
+		}
+	}
+}
+
+// checkRule validates the value with the rule and returns the error message, if any.
+func checkRule(database *db.DB, name string, value reflect.Value, rule string, arg string) string {
+	empty := isEmpty(value)
+	if rule == "required" {
+		if empty {
+			return fmt.Sprintf("The %s field is required.", name)
+		}
+		return ""
+	}
+	// Empty values are only checked by required.
+	if empty {
+		return ""
+	}
+	value = reflect.Indirect(value)
+	text := fmt.Sprint(value.Interface())
+	switch rule {
+	case "email":
+		if address, err := mail.ParseAddress(text); err != nil || address.Address != text {
+			return fmt.Sprintf("The %s must be a valid email address.", name)
+		}
+	case "min", "max":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Sprintf("The %s rule of %s has an invalid argument.", rule, name)
+		}
+		size := sizeOf(value)
+		if rule == "min" && size < limit {
+			return fmt.Sprintf("The %s must be at least %s.", name, arg)
+		}
+		if rule == "max" && size > limit {
+			return fmt.Sprintf("The %s may not be greater than %s.", name, arg)
+		}
+	case "in":
+		for _, option := range strings.Split(arg, ",") {
+			if option == text {
+				return ""
+			}
+		}
+		return fmt.Sprintf("The selected %s is invalid.", name)
+	case "regex":
+		re, err := regexp.Compile(arg)
+		if err != nil || !re.MatchString(text) {
+			return fmt.Sprintf("The %s format is invalid.", name)
+		}
+	case "unique", "exists":
+		count, err := countRecords(database, name, arg, value.Interface())
+		if err != nil {
+			return fmt.Sprintf("The %s could not be checked.", name)
+		}
+		if rule == "unique" && count > 0 {
+			return fmt.Sprintf("The %s has already been taken.", name)
+		}
+		if rule == "exists" && count == 0 {
+			return fmt.Sprintf("The selected %s is invalid.", name)
+		}
+	default:
+		return fmt.Sprintf("The %s rule of %s is unknown.", rule, name)
+	}
+	return ""
+}
+
+// isEmpty determines if the value is the zero value or an empty string, slice or map.
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	case reflect.String:
+		return strings.TrimSpace(value.String()) == ""
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len() == 0
+	}
+	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
+}
+
+// sizeOf returns the length of strings and lists, and the value of numbers.
+func sizeOf(value reflect.Value) float64 {
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String()))
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(value.Len())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	}
+	return 0
+}
+
+// countRecords counts the records of the "table,column" argument with the
+// value, using the field name as the column when it's not given.
+func countRecords(database *db.DB, name string, arg string, value interface{}) (int, error) {
+	if database == nil {
+		return 0, fmt.Errorf("There's no database connection")
+	}
+	parts := strings.SplitN(arg, ",", 2)
+	column := name
+	if len(parts) == 2 {
+		column = parts[1]
+	}
+	var count int
+	err := database.DB.Table(parts[0]).Where(column+" = ?", value).Count(&count).Error
+	return count, err
+}
+
+// FlashErrors stores the errors in a cookie so they're available
+// in the next request with OldErrors.
+func (req *HTTP) FlashErrors(errs Errors) error {
+	data, err := json.Marshal(errs)
+	if err != nil {
+		return err
+	}
+	c := cookie.New(ErrorsCookie, base64.RawURLEncoding.EncodeToString(data))
+	if req.Cookies != nil {
+		if err := req.Cookies.Prepare(c, cookie.Plain); err != nil {
+			return err
+		}
+	}
+	http.SetCookie(req.Writer, c)
+	return nil
+}
+
+// OldErrors returns the validation errors flashed by the previous request,
+// removing them so they're only available once. The bag is never nil.
+func (req *HTTP) OldErrors() Errors {
+	errs := make(Errors)
+	cookie, err := req.Request.Cookie(ErrorsCookie)
+	if err != nil {
+		return errs
+	}
+	http.SetCookie(req.Writer, &http.Cookie{Name: ErrorsCookie, Path: "/", MaxAge: -1})
+	if data, err := base64.RawURLEncoding.DecodeString(cookie.Value); err == nil {
+		json.Unmarshal(data, &errs)
+	}
+	return errs
+}
+
+// WantsJSON determines if the client expects a JSON response.
+func (req *HTTP) WantsJSON() bool {
+	return req.IsJSON() ||
+		strings.Contains(req.Request.Header.Get("Accept"), "json") ||
+		req.Request.Header.Get("X-Requested-With") == "XMLHttpRequest"
+}
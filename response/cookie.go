@@ -0,0 +1,59 @@
+package response
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/pulsar-go/pulsar/cookie"
+	"github.com/pulsar-go/pulsar/request"
+)
+
+// queuedCookie is a cookie set when the response is handled.
+type queuedCookie struct {
+	cookie *http.Cookie
+	mode   cookie.Mode
+}
+
+// WithCookie queues a plain cookie, like one created with cookie.New.
+func (response HTTP) WithCookie(c *http.Cookie) HTTP {
+	return response.withCookie(c, cookie.Plain)
+}
+
+// WithSignedCookie queues a cookie signed with the application key.
+func (response HTTP) WithSignedCookie(c *http.Cookie) HTTP {
+	return response.withCookie(c, cookie.Signed)
+}
+
+// WithEncryptedCookie queues a cookie encrypted with the application key.
+func (response HTTP) WithEncryptedCookie(c *http.Cookie) HTTP {
+	return response.withCookie(c, cookie.Encrypted)
+}
+
+// WithoutCookie queues the removal of a cookie.
+func (response HTTP) WithoutCookie(name string) HTTP {
+	return response.withCookie(&http.Cookie{Name: name, Path: "/", MaxAge: -1}, cookie.Plain)
+}
+
+// withCookie queues a cookie protected with the mode.
+func (response HTTP) withCookie(c *http.Cookie, mode cookie.Mode) HTTP {
+	copied := *c
+	response.cookies = append(append([]queuedCookie(nil), response.cookies...), queuedCookie{cookie: &copied, mode: mode})
+	return response
+}
+
+// setCookies sets the queued cookies using the request cookie jar.
+func (response *HTTP) setCookies(req *request.HTTP) {
+	for _, queued := range response.cookies {
+		c := *queued.cookie
+		if req.Cookies != nil {
+			if err := req.Cookies.Prepare(&c, queued.mode); err != nil {
+				log.Printf("[PULSAR] Unable to set cookie %s: %s\n", c.Name, err)
+				continue
+			}
+		} else if queued.mode != cookie.Plain {
+			log.Printf("[PULSAR] Unable to set cookie %s: %s\n", c.Name, cookie.ErrorNoKey)
+			continue
+		}
+		http.SetCookie(req.Writer, &c)
+	}
+}
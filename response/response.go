@@ -2,6 +2,7 @@ package response
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"html/template"
 	"io/ioutil"
@@ -24,6 +25,10 @@ const (
 	StaticResponse
 	AssetResponse
 	ViewResponse
+	HandlerResponse
+	RedirectResponse
+	XMLResponse
+	NegotiateResponse
 )
 
 // HTTP is the web server response.
@@ -32,6 +37,18 @@ type HTTP struct {
 	Type       Type
 	TextData   string
 	JSONData   interface{}
+	Handler    http.Handler
+	cookies    []queuedCookie
+}
+
+// funcs are the functions available in the views.
+var funcs = template.FuncMap{}
+
+// AddFuncs adds functions that are available in the views.
+func AddFuncs(f template.FuncMap) {
+	for name, fn := range f {
+		funcs[name] = fn
+	}
 }
 
 // Text returns a HTTP response with plain text.
@@ -58,13 +75,60 @@ func JSONWithCode(data interface{}, code int) HTTP {
 	return res
 }
 
+// XML returns a HTTP response with the XML headers.
+func XML(data interface{}) HTTP {
+	return HTTP{StatusCode: http.StatusOK, Type: XMLResponse, JSONData: data}
+}
+
+// XMLWithCode is a XML response with additional status code.
+func XMLWithCode(data interface{}, code int) HTTP {
+	res := XML(data)
+	res.StatusCode = code
+	return res
+}
+
+// Negotiate returns a response that renders the data as JSON, XML or the
+// given view depending on what the client prefers, or a 406 error when it
+// accepts none of them. An empty view name only allows JSON and XML.
+func Negotiate(data interface{}, view string) HTTP {
+	return HTTP{StatusCode: http.StatusOK, Type: NegotiateResponse, TextData: view, JSONData: data}
+}
+
+// NegotiateWithCode is a Negotiate response with additional status code.
+func NegotiateWithCode(data interface{}, view string, code int) HTTP {
+	res := Negotiate(data, view)
+	res.StatusCode = code
+	return res
+}
+
+// negotiate returns the response the client prefers. XML is only offered
+// when the data can be marshaled, which isn't the case of maps.
+func (response *HTTP) negotiate(req *request.HTTP) HTTP {
+	offers := []string{"application/json"}
+	if _, err := xml.Marshal(response.JSONData); err == nil {
+		offers = append(offers, "application/xml", "text/xml")
+	}
+	if response.TextData != "" {
+		offers = append(offers, "text/html")
+	}
+	var res HTTP
+	switch req.Prefers(offers...) {
+	case "application/json":
+		res = JSON(response.JSONData)
+	case "application/xml", "text/xml":
+		res = XML(response.JSONData)
+	case "text/html":
+		res = View(response.TextData, response.JSONData)
+	default:
+		return TextWithCode(http.StatusText(http.StatusNotAcceptable), http.StatusNotAcceptable)
+	}
+	res.StatusCode = response.StatusCode
+	return res
+}
+
 // Static return a View response without templating data.
 func Static(name string) HTTP {
-	path, err := filepath.Abs(filepath.Clean(config.Settings.Views.Path) + "/" + filepath.Clean(name+".html"))
-	if err != nil {
-		log.Println(err)
-	}
-	return HTTP{StatusCode: http.StatusOK, Type: StaticResponse, TextData: path}
+	return HTTP{StatusCode: http.StatusOK, Type: StaticResponse, TextData: name + ".html"}
 }
 
 // StaticWithCode is a Static response with additional status code.
@@ -76,20 +140,12 @@ func StaticWithCode(name string, code int) HTTP {
 
 // Asset return an asset response (css files, js files, images, etc.).
 func Asset(name string) HTTP {
-	path, err := filepath.Abs(filepath.Clean(config.Settings.Views.Path) + "/" + filepath.Clean(name))
-	if err != nil {
-		log.Println(err)
-	}
-	return HTTP{StatusCode: http.StatusOK, Type: AssetResponse, TextData: path}
+	return HTTP{StatusCode: http.StatusOK, Type: AssetResponse, TextData: name}
 }
 
 // View return a View response with templating data.
 func View(name string, data interface{}) HTTP {
-	path, err := filepath.Abs(filepath.Clean(config.Settings.Views.Path) + "/" + filepath.Clean(name+".gohtml"))
-	if err != nil {
-		log.Println(err)
-	}
-	return HTTP{StatusCode: http.StatusOK, Type: ViewResponse, TextData: path, JSONData: data}
+	return HTTP{StatusCode: http.StatusOK, Type: ViewResponse, TextData: name + ".gohtml", JSONData: data}
 }
 
 // ViewWithCode is a View response with additional code.
@@ -99,8 +155,48 @@ func ViewWithCode(name string, data interface{}, code int) HTTP {
 	return res
 }
 
+// viewPath returns the path of the file in the views directory
+// of the application serving the request.
+func viewPath(req *request.HTTP, name string) string {
+	settings := req.Config
+	if settings == nil {
+		settings = config.Current()
+	}
+	path, err := filepath.Abs(filepath.Clean(settings.Views.Path) + "/" + filepath.Clean(name))
+	if err != nil {
+		log.Println(err)
+	}
+	return path
+}
+
+// Handler returns a response served by a net/http handler.
+func Handler(handler http.Handler) HTTP {
+	return HTTP{StatusCode: http.StatusOK, Type: HandlerResponse, Handler: handler}
+}
+
+// Redirect returns a redirect response to the URL.
+func Redirect(url string) HTTP {
+	return HTTP{StatusCode: http.StatusFound, Type: RedirectResponse, TextData: url}
+}
+
+// RedirectWithCode is a Redirect response with additional status code.
+func RedirectWithCode(url string, code int) HTTP {
+	res := Redirect(url)
+	res.StatusCode = code
+	return res
+}
+
+// Back returns a redirect response to the previous page, or to / if unknown.
+func Back(req *request.HTTP) HTTP {
+	if referer := req.Request.Referer(); referer != "" {
+		return Redirect(referer)
+	}
+	return Redirect("/")
+}
+
 // Handle handles the HTTP request using a response writter.
 func (response *HTTP) Handle(req *request.HTTP) {
+	response.setCookies(req)
 	writer := req.Writer
 	switch response.Type {
 	case TextResponse:
@@ -115,16 +211,40 @@ func (response *HTTP) Handle(req *request.HTTP) {
 		writer.WriteHeader(response.StatusCode)
 		fmt.Fprint(writer, string(result))
 	case StaticResponse, AssetResponse:
-		content, err := ioutil.ReadFile(response.TextData)
+		path := viewPath(req, response.TextData)
+		content, err := ioutil.ReadFile(path)
 		if err != nil {
-			log.Println("File " + response.TextData + " not found.")
+			log.Println("File " + path + " not found.")
 		}
-		writer.Header().Set("Content-Type", mime.TypeByExtension(filepath.Ext(response.TextData)))
+		writer.Header().Set("Content-Type", mime.TypeByExtension(filepath.Ext(path)))
 		writer.WriteHeader(response.StatusCode)
 		fmt.Fprint(writer, string(content))
 	case ViewResponse:
+		path := viewPath(req, response.TextData)
+		view := template.New(filepath.Base(path)).Funcs(funcs)
+		if req.URL != nil {
+			view.Funcs(template.FuncMap{"route": req.URL})
+		}
+		writer.WriteHeader(response.StatusCode)
+		template.Must(view.ParseFiles(path)).Execute(writer, response.JSONData)
+	case HandlerResponse:
+		response.Handler.ServeHTTP(writer, req.Request)
+	case XMLResponse:
+		result, err := xml.Marshal(response.JSONData)
+		if err != nil {
+			log.Printf("[PULSAR] Error while marshaling XML: %s\n", err)
+			http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/xml")
 		writer.WriteHeader(response.StatusCode)
-		template.Must(template.ParseFiles(response.TextData)).Execute(writer, response.JSONData)
+		fmt.Fprint(writer, xml.Header+string(result))
+	case NegotiateResponse:
+		writer.Header().Add("Vary", "Accept")
+		res := response.negotiate(req)
+		res.Handle(req)
+	case RedirectResponse:
+		http.Redirect(writer, req.Request, response.TextData, response.StatusCode)
 	default:
 		writer.WriteHeader(response.StatusCode)
 		fmt.Fprint(writer, "Invalid HTTP response type.")
@@ -0,0 +1,35 @@
+package response
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/pulsar-go/pulsar/request"
+)
+
+// Invalid responds to a failed request validation. Clients expecting JSON
+// get a 422 response with the errors, while the rest are redirected back
+// with the errors flashed for the next request. Binding errors respond
+// with a 415, 413 or 400 status code.
+func Invalid(req *request.HTTP, err error) HTTP {
+	errs, ok := err.(request.Errors)
+	if !ok {
+		code := http.StatusBadRequest
+		if _, unsupported := err.(*request.ContentTypeError); unsupported {
+			code = http.StatusUnsupportedMediaType
+		} else if err == request.ErrorBodyTooLarge || req.BodyTooLarge() {
+			code = http.StatusRequestEntityTooLarge
+		}
+		if req.WantsJSON() {
+			return JSONWithCode(map[string]string{"error": err.Error()}, code)
+		}
+		return TextWithCode(err.Error(), code)
+	}
+	if req.WantsJSON() {
+		return JSONWithCode(map[string]interface{}{"errors": errs}, http.StatusUnprocessableEntity)
+	}
+	if err := req.FlashErrors(errs); err != nil {
+		log.Println(err)
+	}
+	return Back(req)
+}
@@ -0,0 +1,20 @@
+package router_test
+
+import (
+	"io"
+	"net/http/httptest"
+
+	"github.com/pulsar-go/pulsar"
+	"github.com/pulsar-go/pulsar/config"
+	"github.com/pulsar-go/pulsar/router"
+)
+
+// serve sends a request to a new application with the routes and returns its response.
+func serve(routes func(r *router.Router), method, target string, body io.Reader) *httptest.ResponseRecorder {
+	c := config.Defaults()
+	app := pulsar.New(&c)
+	routes(app.Router)
+	w := httptest.NewRecorder()
+	app.Handler().ServeHTTP(w, httptest.NewRequest(method, target, body))
+	return w
+}
@@ -0,0 +1,103 @@
+package router
+
+import (
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/pulsar-go/pulsar/db"
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+)
+
+// Bind binds a route parameter to a model type, which must be a pointer to a
+// struct embedding db.Model. The routes of the router and its groups with that
+// parameter load the record using the parameter as its id before the handler runs,
+// and respond with the not found handler when the record is missing or deleted.
+func (r *Router) Bind(param string, model interface{}) *Router {
+	t := reflect.TypeOf(model)
+	if t == nil || t.Kind() != reflect.Ptr || !db.EmbedsModel(t.Elem()) {
+		panic("router: the model bound to '" + param + "' must be a pointer to a struct embedding db.Model")
+	}
+	if r.bindings == nil {
+		r.bindings = make(map[string]reflect.Type)
+	}
+	r.bindings[param] = t.Elem()
+	return r
+}
+
+// binding returns the model type bound to the parameter in the router or its parents.
+func (r *Router) binding(param string) (reflect.Type, bool) {
+	if t, ok := r.bindings[param]; ok {
+		return t, true
+	}
+	if r.parent != nil {
+		return r.parent.binding(param)
+	}
+	return nil, false
+}
+
+// database returns the database used to load the bound models.
+func (r *Router) database() *db.DB {
+	if r.Database != nil {
+		return r.Database
+	}
+	if r.parent != nil {
+		return r.parent.database()
+	}
+	return db.Builder
+}
+
+// root returns the root router.
+func (r *Router) root() *Router {
+	if r.parent != nil {
+		return r.parent.root()
+	}
+	return r
+}
+
+// notFound responds using the not found handler of the root router.
+func (r *Router) notFound(req *request.HTTP) response.HTTP {
+	if handler := r.root().NotFoundHandler; handler != nil {
+		return handler(req)
+	}
+	return notFound(req)
+}
+
+// bind wraps the handler so it loads the models bound to the route parameters.
+func (route *Route) bind(handler Handler) Handler {
+	if route.router == nil {
+		return handler
+	}
+	bindings := make(map[string]reflect.Type)
+	for _, segment := range strings.Split(route.URI, "/") {
+		if strings.HasPrefix(segment, ":") {
+			if t, ok := route.router.binding(segment[1:]); ok {
+				bindings[segment[1:]] = t
+			}
+		}
+	}
+	if len(bindings) == 0 {
+		return handler
+	}
+	r := route.router
+	return func(req *request.HTTP) response.HTTP {
+		for param, t := range bindings {
+			model := reflect.New(t).Interface()
+			result := r.database().Where("id", req.Params.ByName(param)).First(model)
+			if result.RecordNotFound() {
+				return r.notFound(req)
+			}
+			if result.Error != nil {
+				log.Printf("[PULSAR] Failed to bind %s: %s\n", param, result.Error)
+				return response.TextWithCode(http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			if req.Models == nil {
+				req.Models = make(map[string]interface{})
+			}
+			req.Models[param] = model
+		}
+		return handler(req)
+	}
+}
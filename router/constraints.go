@@ -0,0 +1,70 @@
+package router
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+)
+
+// Constraint determines if a route parameter value is valid.
+type Constraint func(value string) bool
+
+// Numeric only allows unsigned integer values.
+func Numeric(value string) bool {
+	_, err := strconv.ParseUint(value, 10, 64)
+	return err == nil
+}
+
+// UUID only allows UUID values.
+func UUID(value string) bool {
+	return request.IsUUID(value)
+}
+
+// Regex only allows values fully matching the pattern.
+func Regex(pattern string) Constraint {
+	re := regexp.MustCompile("^(?:" + pattern + ")$")
+	return re.MatchString
+}
+
+// Enum only allows the given values.
+func Enum(values ...string) Constraint {
+	return func(value string) bool {
+		return contains(values, value)
+	}
+}
+
+// Where constrains a parameter of the routes added by the last call.
+// Requests with invalid values respond with the not found handler
+// before the middlewares and the handler run.
+func (r *Router) Where(param string, constraint Constraint) *Router {
+	for i := r.last; i < len(r.Routes); i++ {
+		if r.Routes[i].Constraints == nil {
+			r.Routes[i].Constraints = make(map[string]Constraint)
+		}
+		r.Routes[i].Constraints[param] = constraint
+	}
+	return r
+}
+
+// constrain wraps the handler so it checks the route parameter constraints.
+func (route *Route) constrain(handler Handler) Handler {
+	if len(route.Constraints) == 0 {
+		return handler
+	}
+	constraints := route.Constraints
+	r := route.router
+	return func(req *request.HTTP) response.HTTP {
+		for param, constraint := range constraints {
+			if !constraint(strings.TrimPrefix(req.Params.ByName(param), "/")) {
+				if r == nil {
+					return notFound(req)
+				}
+				return r.notFound(req)
+			}
+		}
+		return handler(req)
+	}
+}
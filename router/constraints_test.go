@@ -0,0 +1,57 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+	"github.com/pulsar-go/pulsar/router"
+)
+
+func TestConstraints(t *testing.T) {
+	routes := func(r *router.Router) {
+		r.NotFound(func(req *request.HTTP) response.HTTP {
+			return response.TextWithCode("missing", http.StatusNotFound)
+		})
+		r.Get("/numbers/:id", home).Where("id", router.Numeric)
+		r.Get("/uuids/:id", home).Where("id", router.UUID)
+		r.Get("/slugs/:slug", home).Where("slug", router.Regex("[a-z]+(-[a-z]+)*"))
+		r.Get("/sizes/:size", home).Where("size", router.Enum("small", "large"))
+		r.Get("/files/*path", home).Where("path", router.Regex(`[a-z/]+\.txt`))
+		r.Group(&router.Options{Prefix: "/admin"}, func(r *router.Router) {
+			r.Get("/users/:user", home, func(next router.Handler) router.Handler {
+				return func(req *request.HTTP) response.HTTP {
+					return response.Text("middleware")
+				}
+			}).Where("user", router.Numeric)
+		})
+	}
+	tests := []struct {
+		target string
+		code   int
+		body   string
+	}{
+		{"/numbers/42", http.StatusOK, "/numbers/42"},
+		{"/numbers/-1", http.StatusNotFound, "missing"},
+		{"/numbers/4x", http.StatusNotFound, "missing"},
+		{"/uuids/123e4567-e89b-12d3-a456-426614174000", http.StatusOK, "/uuids/123e4567-e89b-12d3-a456-426614174000"},
+		{"/uuids/123e4567", http.StatusNotFound, "missing"},
+		{"/slugs/hello-world", http.StatusOK, "/slugs/hello-world"},
+		{"/slugs/hello-", http.StatusNotFound, "missing"},
+		{"/slugs/xhello-world1", http.StatusNotFound, "missing"},
+		{"/sizes/large", http.StatusOK, "/sizes/large"},
+		{"/sizes/medium", http.StatusNotFound, "missing"},
+		{"/files/docs/a.txt", http.StatusOK, "/files/docs/a.txt"},
+		{"/files/docs/a.pdf", http.StatusNotFound, "missing"},
+		// The constraints are checked before the middlewares run.
+		{"/admin/users/1", http.StatusOK, "middleware"},
+		{"/admin/users/me", http.StatusNotFound, "missing"},
+	}
+	for _, tt := range tests {
+		w := serve(routes, http.MethodGet, tt.target, nil)
+		if w.Code != tt.code || w.Body.String() != tt.body {
+			t.Errorf("GET %s = %d %q, want %d %q", tt.target, w.Code, w.Body.String(), tt.code, tt.body)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package router
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// domainParam matches the {name} placeholders of a domain pattern.
+var domainParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Domain represents a host pattern like {tenant}.example.com
+// where every placeholder captures a single host label.
+type Domain struct {
+	Pattern string
+	regex   *regexp.Regexp
+	names   []string
+}
+
+// ParseDomain parses a domain pattern.
+func ParseDomain(pattern string) *Domain {
+	d := &Domain{Pattern: pattern}
+	expr := "(?i)^"
+	last := 0
+	for _, match := range domainParam.FindAllStringSubmatchIndex(pattern, -1) {
+		expr += regexp.QuoteMeta(pattern[last:match[0]]) + "([^.]+)"
+		d.names = append(d.names, pattern[match[2]:match[3]])
+		last = match[1]
+	}
+	d.regex = regexp.MustCompile(expr + regexp.QuoteMeta(pattern[last:]) + "$")
+	return d
+}
+
+// Match determines if the host, with or without port, matches
+// the domain and returns the captured placeholders.
+func (d *Domain) Match(host string) (httprouter.Params, bool) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	matches := d.regex.FindStringSubmatch(strings.TrimSuffix(host, "."))
+	if matches == nil {
+		return nil, false
+	}
+	params := make(httprouter.Params, len(d.names))
+	for i, name := range d.names {
+		params[i] = httprouter.Param{Key: name, Value: matches[i+1]}
+	}
+	return params, true
+}
@@ -0,0 +1,86 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+	"github.com/pulsar-go/pulsar/router"
+)
+
+func TestDomainMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		host    string
+		match   bool
+		params  map[string]string
+	}{
+		{"example.com", "example.com", true, nil},
+		{"example.com", "EXAMPLE.com:8080", true, nil},
+		{"example.com", "example.com.", true, nil},
+		{"example.com", "www.example.com", false, nil},
+		{"{tenant}.example.com", "acme.example.com", true, map[string]string{"tenant": "acme"}},
+		{"{tenant}.example.com", "acme.example.com:443", true, map[string]string{"tenant": "acme"}},
+		{"{tenant}.example.com", "a.b.example.com", false, nil},
+		{"{tenant}.example.com", "example.com", false, nil},
+		{"{app}.{region}.example.com", "api.eu.example.com", true, map[string]string{"app": "api", "region": "eu"}},
+		{"api.example.com", "apixexample.com", false, nil},
+	}
+	for _, tt := range tests {
+		params, ok := router.ParseDomain(tt.pattern).Match(tt.host)
+		if ok != tt.match {
+			t.Errorf("%s Match(%q) = %v, want %v", tt.pattern, tt.host, ok, tt.match)
+			continue
+		}
+		for name, value := range tt.params {
+			if got := params.ByName(name); got != value {
+				t.Errorf("%s Match(%q) %s = %q, want %q", tt.pattern, tt.host, name, got, value)
+			}
+		}
+	}
+}
+
+func TestDomainRoutes(t *testing.T) {
+	routes := func(r *router.Router) {
+		r.Get("/only", home)
+		r.Get("/dashboard", func(req *request.HTTP) response.HTTP {
+			return response.Text("global dashboard")
+		})
+		r.Group(&router.Options{Domain: "{tenant}.example.com"}, func(r *router.Router) {
+			r.Get("/dashboard", func(req *request.HTTP) response.HTTP {
+				return response.Text("dashboard of " + req.DomainParams.ByName("tenant"))
+			})
+			r.Get("/tenant", home)
+		})
+		r.Group(&router.Options{Domain: "admin.example.com"}, func(r *router.Router) {
+			r.Get("/admin", home)
+		})
+	}
+	tests := []struct {
+		method string
+		target string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "http://acme.example.com/dashboard", http.StatusOK, "dashboard of acme"},
+		{http.MethodGet, "http://acme.example.com:8080/dashboard", http.StatusOK, "dashboard of acme"},
+		{http.MethodGet, "http://example.com/dashboard", http.StatusOK, "global dashboard"},
+		// The routes without a domain serve every host.
+		{http.MethodGet, "http://acme.example.com/only", http.StatusOK, "/only"},
+		{http.MethodGet, "http://example.com/only", http.StatusOK, "/only"},
+		{http.MethodGet, "http://example.com/tenant", http.StatusNotFound, ""},
+		// The first matching domain is used, so admin is a tenant.
+		{http.MethodGet, "http://admin.example.com/dashboard", http.StatusOK, "dashboard of admin"},
+		{http.MethodGet, "http://admin.example.com/admin", http.StatusOK, "/admin"},
+		{http.MethodGet, "http://acme.example.com/admin", http.StatusNotFound, ""},
+		// The domain routes keep their method not allowed responses.
+		{http.MethodPost, "http://acme.example.com/tenant", http.StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		w := serve(routes, tt.method, tt.target, nil)
+		if w.Code != tt.code || tt.body != "" && w.Body.String() != tt.body {
+			t.Errorf("%s %s = %d %q, want %d %q", tt.method, tt.target, w.Code, w.Body.String(), tt.code, tt.body)
+		}
+	}
+}
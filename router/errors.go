@@ -0,0 +1,29 @@
+package router
+
+import (
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+)
+
+// RecoverHandler represents the handler of a route that panicked,
+// receiving the value the route panicked with.
+type RecoverHandler func(req *request.HTTP, recovered interface{}) response.HTTP
+
+// NotFound sets the handler used when no route matches the request.
+func (r *Router) NotFound(handler Handler) *Router {
+	r.NotFoundHandler = handler
+	return r
+}
+
+// MethodNotAllowed sets the handler used when a route matches
+// the request path but not the request method.
+func (r *Router) MethodNotAllowed(handler Handler) *Router {
+	r.MethodNotAllowedHandler = handler
+	return r
+}
+
+// Recover sets the handler used when a route panics.
+func (r *Router) Recover(handler RecoverHandler) *Router {
+	r.RecoverHandler = handler
+	return r
+}
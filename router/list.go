@@ -0,0 +1,79 @@
+package router
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+)
+
+// Info describes a route as it's registered.
+type Info struct {
+	Method      string
+	Domain      string
+	URI         string
+	Name        string
+	Middlewares []string
+	Handler     string
+}
+
+// List returns every route of the router and its groups with the
+// full URI and the names of its middleware chain and handler.
+func (r *Router) List() []Info {
+	var list []Info
+	for i := range r.Routes {
+		route := &r.Routes[i]
+		chain := route.Chain()
+		middlewares := make([]string, len(chain))
+		for j, middleware := range chain {
+			middlewares[j] = funcName(middleware)
+		}
+		handler := funcName(route.Handler)
+		if route.Controller != "" {
+			// Resource routes show the controller method, since
+			// their handlers are method values of its interfaces.
+			handler = route.Controller + "." + strings.ToUpper(route.Action[:1]) + route.Action[1:]
+		}
+		list = append(list, Info{
+			Method:      route.HTTPMethod(),
+			Domain:      route.Domain,
+			URI:         route.URI,
+			Name:        route.Name,
+			Middlewares: middlewares,
+			Handler:     handler,
+		})
+	}
+	for _, child := range r.Childs {
+		list = append(list, child.List()...)
+	}
+	return list
+}
+
+// Print writes the routes of the router as a table.
+func (r *Router) Print(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "METHOD\tDOMAIN\tURI\tNAME\tMIDDLEWARES\tHANDLER")
+	for _, info := range r.List() {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", info.Method, info.Domain, info.URI, info.Name, strings.Join(info.Middlewares, ", "), info.Handler)
+	}
+	return table.Flush()
+}
+
+// List returns every global route.
+func List() []Info {
+	return Routes.List()
+}
+
+// funcName returns the name of the function.
+func funcName(f interface{}) string {
+	value := reflect.ValueOf(f)
+	if value.Kind() != reflect.Func || value.IsNil() {
+		return ""
+	}
+	if fn := runtime.FuncForPC(value.Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package router_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+	"github.com/pulsar-go/pulsar/router"
+)
+
+func TestList(t *testing.T) {
+	r := &router.Router{}
+	r.Use(logger)
+	r.Get("/", home).Name("home")
+	r.Resource("/photos", &photos{}, router.Only(router.IndexAction, router.ShowAction))
+	r.Group(&router.Options{Prefix: "/admin", Domain: "admin.example.com"}, func(r *router.Router) {
+		r.Get("/users", home)
+	})
+	tests := []struct {
+		method      string
+		domain      string
+		uri         string
+		name        string
+		middlewares []string
+		handler     string
+	}{
+		{"GET", "", "/", "home", []string{"router_test.logger"}, "router_test.home"},
+		{"GET", "", "/photos", "photos.index", []string{"router_test.logger"}, "router_test.(*photos).Index"},
+		{"GET", "", "/photos/:id", "photos.show", []string{"router_test.logger"}, "router_test.(*photos).Show"},
+		{"GET", "admin.example.com", "/admin/users", "", []string{"router_test.logger"}, "router_test.home"},
+	}
+	list := r.List()
+	if len(list) != len(tests) {
+		t.Fatalf("List() = %+v, want %d routes", list, len(tests))
+	}
+	for i, tt := range tests {
+		info := list[i]
+		if info.Method != tt.method || info.Domain != tt.domain || info.URI != tt.uri || info.Name != tt.name {
+			t.Errorf("List()[%d] = %+v, want %s %s%s named %q", i, info, tt.method, tt.domain, tt.uri, tt.name)
+		}
+		if middlewares := trimPackages(info.Middlewares); !reflect.DeepEqual(middlewares, tt.middlewares) {
+			t.Errorf("List()[%d].Middlewares = %q, want %q", i, middlewares, tt.middlewares)
+		}
+		if handler := trimPackages([]string{info.Handler})[0]; handler != tt.handler {
+			t.Errorf("List()[%d].Handler = %q, want %q", i, handler, tt.handler)
+		}
+	}
+}
+
+// trimPackages removes the package path of the function names.
+func trimPackages(names []string) []string {
+	trimmed := make([]string, len(names))
+	for i, name := range names {
+		trimmed[i] = name[strings.LastIndex(name, "/")+1:]
+	}
+	return trimmed
+}
+
+// logger is a middleware that does nothing.
+func logger(next router.Handler) router.Handler {
+	return next
+}
+
+// home responds with the path of the request.
+func home(req *request.HTTP) response.HTTP {
+	return response.Text(req.Request.URL.Path)
+}
+
+// photos is a resource controller responding with its actions.
+type photos struct{}
+
+func (*photos) Index(req *request.HTTP) response.HTTP  { return response.Text("index") }
+func (*photos) Create(req *request.HTTP) response.HTTP { return response.Text("create") }
+func (*photos) Store(req *request.HTTP) response.HTTP  { return response.Text("store") }
+func (*photos) Show(req *request.HTTP) response.HTTP {
+	return response.Text("show " + req.Params.ByName("id"))
+}
+func (*photos) Edit(req *request.HTTP) response.HTTP {
+	return response.Text("edit " + req.Params.ByName("id"))
+}
+func (*photos) Update(req *request.HTTP) response.HTTP {
+	return response.Text("update " + req.Params.ByName("id"))
+}
+func (*photos) Destroy(req *request.HTTP) response.HTTP {
+	return response.Text("destroy " + req.Params.ByName("id"))
+}
@@ -0,0 +1,217 @@
+package router
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+)
+
+// The resource controller actions.
+const (
+	IndexAction   = "index"
+	CreateAction  = "create"
+	StoreAction   = "store"
+	ShowAction    = "show"
+	EditAction    = "edit"
+	UpdateAction  = "update"
+	DestroyAction = "destroy"
+)
+
+// Indexer lists the resources: GET /photos
+type Indexer interface {
+	Index(req *request.HTTP) response.HTTP
+}
+
+// Creator shows the form to create a resource: GET /photos/create
+type Creator interface {
+	Create(req *request.HTTP) response.HTTP
+}
+
+// Storer stores a new resource: POST /photos
+type Storer interface {
+	Store(req *request.HTTP) response.HTTP
+}
+
+// Shower shows a resource: GET /photos/:id
+type Shower interface {
+	Show(req *request.HTTP) response.HTTP
+}
+
+// Editor shows the form to edit a resource: GET /photos/:id/edit
+type Editor interface {
+	Edit(req *request.HTTP) response.HTTP
+}
+
+// Updater updates a resource: PUT and PATCH /photos/:id
+type Updater interface {
+	Update(req *request.HTTP) response.HTTP
+}
+
+// Destroyer deletes a resource: DELETE /photos/:id
+type Destroyer interface {
+	Destroy(req *request.HTTP) response.HTTP
+}
+
+// Filter determines if a resource action is registered.
+type Filter func(action string) bool
+
+// Only registers only the given resource actions.
+func Only(actions ...string) Filter {
+	return func(action string) bool {
+		return contains(actions, action)
+	}
+}
+
+// Except registers all but the given resource actions.
+func Except(actions ...string) Filter {
+	return func(action string) bool {
+		return !contains(actions, action)
+	}
+}
+
+// contains determines if the list contains the value.
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Resource creates the routes of the actions the controller implements,
+// named after the URI (for example photos.index). The create route is an alias
+// served by the GET /photos/:id route when the show or edit actions exist,
+// because the router doesn't allow a static segment next to a parameter.
+func (r *Router) Resource(uri string, controller interface{}, filters ...Filter) *Router {
+	return r.resource(uri, controller, filters, true)
+}
+
+// APIResource creates the routes of a resource without the create and edit actions.
+func (r *Router) APIResource(uri string, controller interface{}, filters ...Filter) *Router {
+	return r.resource(uri, controller, filters, false)
+}
+
+// resource creates the resource routes.
+func (r *Router) resource(uri string, controller interface{}, filters []Filter, forms bool) *Router {
+	handlers := actionHandlers(controller)
+	for action := range handlers {
+		if !forms && (action == CreateAction || action == EditAction) {
+			delete(handlers, action)
+			continue
+		}
+		for _, filter := range filters {
+			if !filter(action) {
+				delete(handlers, action)
+				break
+			}
+		}
+	}
+	name := resourceName(uri)
+	member := uri + "/:id"
+	start := len(r.Routes)
+	if h, ok := handlers[IndexAction]; ok {
+		r.Get(uri, h).Name(name + "." + IndexAction)
+	}
+	if h, ok := handlers[StoreAction]; ok {
+		r.Post(uri, h).Name(name + "." + StoreAction)
+	}
+	create, hasCreate := handlers[CreateAction]
+	show, hasShow := handlers[ShowAction]
+	_, hasEdit := handlers[EditAction]
+	if hasCreate {
+		r.Get(uri+"/"+CreateAction, create).Name(name + "." + CreateAction)
+	}
+	switch {
+	case hasShow:
+		r.Get(member, show).Name(name + "." + ShowAction)
+	case hasCreate && hasEdit:
+		r.Get(member, notFound)
+	}
+	if hasCreate && (hasShow || hasEdit) {
+		r.Routes[len(r.Routes)-2].Alias = true
+		r.Routes[len(r.Routes)-1].aliases = map[string]Route{CreateAction: r.Routes[len(r.Routes)-2]}
+	}
+	if h, ok := handlers[EditAction]; ok {
+		r.Get(member+"/"+EditAction, h).Name(name + "." + EditAction)
+	}
+	if h, ok := handlers[UpdateAction]; ok {
+		r.Match([]string{http.MethodPut, http.MethodPatch}, member, h).Name(name + "." + UpdateAction)
+	}
+	if h, ok := handlers[DestroyAction]; ok {
+		r.Delete(member, h).Name(name + "." + DestroyAction)
+	}
+	// Record the controller of the action routes, which are the named ones.
+	controllerName := typeName(controller)
+	for i := start; i < len(r.Routes); i++ {
+		if route := &r.Routes[i]; route.Name != "" {
+			route.Controller = controllerName
+			route.Action = strings.TrimPrefix(route.Name, name+".")
+		}
+	}
+	return r
+}
+
+// typeName returns the name of the controller type as it's
+// printed in the method names, like controllers.(*Photos).
+func typeName(controller interface{}) string {
+	t := reflect.TypeOf(controller)
+	pointer := t.Kind() == reflect.Ptr
+	if pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t.Name() == "":
+		return reflect.TypeOf(controller).String()
+	case pointer:
+		return t.PkgPath() + ".(*" + t.Name() + ")"
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// actionHandlers returns the handlers of the actions the controller implements.
+func actionHandlers(controller interface{}) map[string]Handler {
+	handlers := make(map[string]Handler)
+	if c, ok := controller.(Indexer); ok {
+		handlers[IndexAction] = c.Index
+	}
+	if c, ok := controller.(Creator); ok {
+		handlers[CreateAction] = c.Create
+	}
+	if c, ok := controller.(Storer); ok {
+		handlers[StoreAction] = c.Store
+	}
+	if c, ok := controller.(Shower); ok {
+		handlers[ShowAction] = c.Show
+	}
+	if c, ok := controller.(Editor); ok {
+		handlers[EditAction] = c.Edit
+	}
+	if c, ok := controller.(Updater); ok {
+		handlers[UpdateAction] = c.Update
+	}
+	if c, ok := controller.(Destroyer); ok {
+		handlers[DestroyAction] = c.Destroy
+	}
+	return handlers
+}
+
+// notFound responds with a not found error.
+func notFound(req *request.HTTP) response.HTTP {
+	return response.TextWithCode(http.StatusText(http.StatusNotFound), http.StatusNotFound)
+}
+
+// resourceName returns the route name prefix of a resource URI,
+// for example users.photos for /users/:user/photos.
+func resourceName(uri string) string {
+	var parts []string
+	for _, segment := range strings.Split(uri, "/") {
+		if segment != "" && segment[0] != ':' && segment[0] != '*' {
+			parts = append(parts, segment)
+		}
+	}
+	return strings.Join(parts, ".")
+}
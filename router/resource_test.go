@@ -0,0 +1,105 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pulsar-go/pulsar/db"
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+	"github.com/pulsar-go/pulsar/router"
+)
+
+// photo is a model bound to the resource routes.
+type photo struct {
+	db.Model
+}
+
+// createOnly is a resource controller with the create and edit actions only.
+type createOnly struct{}
+
+func (createOnly) Create(req *request.HTTP) response.HTTP { return response.Text("create") }
+func (createOnly) Edit(req *request.HTTP) response.HTTP   { return response.Text("edit") }
+
+func TestResource(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes func(r *router.Router)
+		method string
+		target string
+		code   int
+		body   string
+	}{
+		{"index", resource(), http.MethodGet, "/photos", http.StatusOK, "index"},
+		{"create", resource(), http.MethodGet, "/photos/create", http.StatusOK, "create"},
+		{"store", resource(), http.MethodPost, "/photos", http.StatusOK, "store"},
+		{"show", resource(), http.MethodGet, "/photos/1", http.StatusOK, "show 1"},
+		{"edit", resource(), http.MethodGet, "/photos/1/edit", http.StatusOK, "edit 1"},
+		{"put", resource(), http.MethodPut, "/photos/1", http.StatusOK, "update 1"},
+		{"patch", resource(), http.MethodPatch, "/photos/1", http.StatusOK, "update 1"},
+		{"destroy", resource(), http.MethodDelete, "/photos/1", http.StatusOK, "destroy 1"},
+		{"only", resource(router.Only(router.ShowAction)), http.MethodGet, "/photos/create", http.StatusOK, "show create"},
+		{"only excluded", resource(router.Only(router.ShowAction)), http.MethodGet, "/photos", http.StatusNotFound, ""},
+		{"except", resource(router.Except(router.ShowAction)), http.MethodGet, "/photos/create", http.StatusOK, "create"},
+		{"except excluded", resource(router.Except(router.ShowAction)), http.MethodGet, "/photos/1", http.StatusNotFound, ""},
+		{"api create", apiResource(), http.MethodGet, "/photos/create", http.StatusOK, "show create"},
+		{"api edit", apiResource(), http.MethodGet, "/photos/1/edit", http.StatusNotFound, ""},
+		{"api show", apiResource(), http.MethodGet, "/photos/1", http.StatusOK, "show 1"},
+		{"create without show", func(r *router.Router) { r.Resource("/photos", createOnly{}) }, http.MethodGet, "/photos/create", http.StatusOK, "create"},
+		{"member without show", func(r *router.Router) { r.Resource("/photos", createOnly{}) }, http.MethodGet, "/photos/1", http.StatusNotFound, ""},
+		{"nested", func(r *router.Router) { r.Resource("/users/:user/photos", &photos{}) }, http.MethodGet, "/users/2/photos/create", http.StatusOK, "create"},
+		// The create alias is served before loading the bound
+		// model, so it doesn't reach the missing database.
+		{"bound create", func(r *router.Router) {
+			r.Bind("id", &photo{}).Resource("/photos", &photos{})
+		}, http.MethodGet, "/photos/create", http.StatusOK, "create"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(tt.routes, tt.method, tt.target, nil)
+			if w.Code != tt.code {
+				t.Fatalf("%s %s = %d, want %d", tt.method, tt.target, w.Code, tt.code)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("%s %s = %q, want %q", tt.method, tt.target, w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestResourceNames(t *testing.T) {
+	r := &router.Router{}
+	r.Resource("/users/:user/photos", &photos{})
+	tests := []struct {
+		name   string
+		params []interface{}
+		url    string
+	}{
+		{"users.photos.index", []interface{}{1}, "/users/1/photos"},
+		{"users.photos.create", []interface{}{1}, "/users/1/photos/create"},
+		{"users.photos.store", []interface{}{1}, "/users/1/photos"},
+		{"users.photos.show", []interface{}{1, 2}, "/users/1/photos/2"},
+		{"users.photos.edit", []interface{}{1, 2}, "/users/1/photos/2/edit"},
+		{"users.photos.update", []interface{}{1, 2}, "/users/1/photos/2"},
+		{"users.photos.destroy", []interface{}{1, 2}, "/users/1/photos/2"},
+	}
+	for _, tt := range tests {
+		if url, err := r.URL(tt.name, tt.params...); err != nil || url != tt.url {
+			t.Errorf("URL(%q) = %q, %v, want %q", tt.name, url, err, tt.url)
+		}
+	}
+}
+
+// resource returns the routes of the photos resource with the filters.
+func resource(filters ...router.Filter) func(r *router.Router) {
+	return func(r *router.Router) {
+		r.Resource("/photos", &photos{}, filters...)
+	}
+}
+
+// apiResource returns the routes of the photos API resource.
+func apiResource() func(r *router.Router) {
+	return func(r *router.Router) {
+		r.APIResource("/photos", &photos{})
+	}
+}
@@ -1,6 +1,11 @@
 package router
 
 import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/pulsar-go/pulsar/db"
 	"github.com/pulsar-go/pulsar/request"
 	"github.com/pulsar-go/pulsar/response"
 )
@@ -13,74 +18,208 @@ type Middleware func(next Handler) Handler
 
 // Options represents the route options.
 type Options struct {
-	Prefix     string
-	Middleware Middleware
+	Prefix string
+	// Domain restricts the routes to the hosts matching
+	// the pattern, like {tenant}.example.com.
+	Domain      string
+	Middleware  Middleware
+	Middlewares []Middleware
 }
 
 // Route is the definition of a route.
 type Route struct {
-	URI     string
-	Method  request.Type
-	Handler Handler
+	Name        string
+	Domain      string
+	URI         string
+	Method      request.Type
+	Verb        string
+	Handler     Handler
+	Middlewares []Middleware
+	Constraints map[string]Constraint
+	// Controller is the type of the resource controller handling the
+	// route, like controllers.(*Photos), and Action its resource action.
+	Controller string
+	Action     string
+	// Alias determines if the route is served by another route with a
+	// parameter in place of its last segment, since the router doesn't
+	// allow a static segment next to a parameter. It isn't registered.
+	Alias bool
+	// aliases are the routes served by the route, by the value of its last parameter.
+	aliases map[string]Route
+	router  *Router
 }
 
 // Router determines how a route is.
 type Router struct {
-	Routes  []Route
-	options Options
-	Childs  []*Router
+	Routes      []Route
+	options     Options
+	Childs      []*Router
+	parent      *Router
+	last        int
+	prefix      string
+	domain      string
+	middlewares []Middleware
+	bindings    map[string]reflect.Type
+	// Database is used to load the bound models, db.Builder when nil.
+	Database *db.DB
+	// The error handlers, only used in the root router.
+	NotFoundHandler         Handler
+	MethodNotAllowedHandler Handler
+	RecoverHandler          RecoverHandler
 }
 
 // Routes representrs the global application routes.
 var Routes Router
 
 // Adds the route to the given router.
-func addRoute(r *Router, uri string, handler Handler, method request.Type) *Router {
-	h := handler
+func addRoute(r *Router, uri string, handler Handler, method request.Type, middlewares []Middleware) *Router {
+	return addRoutes(r, []string{method.String()}, uri, handler, middlewares)
+}
+
+// Adds a route for each HTTP method to the given router.
+func addRoutes(r *Router, methods []string, uri string, handler Handler, middlewares []Middleware) *Router {
+	r.last = len(r.Routes)
+	// Append the routes to the list.
+	for _, method := range methods {
+		r.Routes = append(
+			r.Routes,
+			Route{Domain: r.domain, URI: r.prefix + uri, Method: request.TypeOf(method), Verb: method, Handler: handler, Middlewares: middlewares, router: r},
+		)
+	}
+	return r
+}
+
+// HTTPMethod returns the HTTP method of the route.
+func (route *Route) HTTPMethod() string {
+	if route.Verb != "" {
+		return route.Verb
+	}
+	return route.Method.String()
+}
+
+// chain returns the middlewares of the router, including the ones inherited
+// from its parents, from the outermost to the innermost.
+func (r *Router) chain() []Middleware {
+	var chain []Middleware
+	if r.parent != nil {
+		chain = r.parent.chain()
+	}
 	if r.options.Middleware != nil {
-		h = r.options.Middleware(handler)
+		chain = append(chain, r.options.Middleware)
+	}
+	chain = append(chain, r.options.Middlewares...)
+	return append(chain, r.middlewares...)
+}
+
+// Chain returns the middlewares applied to the route, from the outermost
+// to the innermost: the global ones, the group ones and the route ones.
+func (route *Route) Chain() []Middleware {
+	var chain []Middleware
+	if route.router != nil {
+		chain = route.router.chain()
+	}
+	return append(chain, route.Middlewares...)
+}
+
+// Build returns the route handler wrapped by its middleware chain.
+// The parameter constraints are checked before the middlewares
+// run and the bound models are loaded after them.
+func (route *Route) Build() Handler {
+	h := route.bind(route.Handler)
+	chain := route.Chain()
+	for i := len(chain) - 1; i >= 0; i-- {
+		h = chain[i](h)
 	}
-	// Append the route to the list.
-	r.Routes = append(
-		r.Routes,
-		Route{URI: r.options.Prefix + uri, Method: method, Handler: h},
-	)
+	return route.alias(route.constrain(h))
+}
+
+// alias wraps the handler so the values of the last parameter
+// that belong to an alias route are served by that route.
+func (route *Route) alias(handler Handler) Handler {
+	if len(route.aliases) == 0 {
+		return handler
+	}
+	param := route.URI[strings.LastIndex(route.URI, "/")+2:]
+	aliases := make(map[string]Handler)
+	for value, alias := range route.aliases {
+		alias := alias
+		aliases[value] = alias.Build()
+	}
+	return func(req *request.HTTP) response.HTTP {
+		if alias, ok := aliases[req.Params.ByName(param)]; ok {
+			return alias(req)
+		}
+		return handler(req)
+	}
+}
+
+// Use adds middlewares to every route of the router and its groups.
+func (r *Router) Use(middlewares ...Middleware) *Router {
+	r.middlewares = append(r.middlewares, middlewares...)
 	return r
 }
 
 // Get creates a GET route.
-func (r *Router) Get(uri string, handler Handler) *Router {
-	return addRoute(r, uri, handler, request.GetRequest)
+func (r *Router) Get(uri string, handler Handler, middlewares ...Middleware) *Router {
+	return addRoute(r, uri, handler, request.GetRequest, middlewares)
 }
 
 // Head creates a HEAD route.
-func (r *Router) Head(uri string, handler Handler) *Router {
-	return addRoute(r, uri, handler, request.HeadRequest)
+func (r *Router) Head(uri string, handler Handler, middlewares ...Middleware) *Router {
+	return addRoute(r, uri, handler, request.HeadRequest, middlewares)
 }
 
 // Post creates a POST route.
-func (r *Router) Post(uri string, handler Handler) *Router {
-	return addRoute(r, uri, handler, request.PostRequest)
+func (r *Router) Post(uri string, handler Handler, middlewares ...Middleware) *Router {
+	return addRoute(r, uri, handler, request.PostRequest, middlewares)
 }
 
 // Put creates a PUT route.
-func (r *Router) Put(uri string, handler Handler) *Router {
-	return addRoute(r, uri, handler, request.PutRequest)
+func (r *Router) Put(uri string, handler Handler, middlewares ...Middleware) *Router {
+	return addRoute(r, uri, handler, request.PutRequest, middlewares)
 }
 
 // Patch creates a PATCH route.
-func (r *Router) Patch(uri string, handler Handler) *Router {
-	return addRoute(r, uri, handler, request.PatchRequest)
+func (r *Router) Patch(uri string, handler Handler, middlewares ...Middleware) *Router {
+	return addRoute(r, uri, handler, request.PatchRequest, middlewares)
 }
 
 // Delete creates a DELETE route.
-func (r *Router) Delete(uri string, handler Handler) *Router {
-	return addRoute(r, uri, handler, request.DeleteRequest)
+func (r *Router) Delete(uri string, handler Handler, middlewares ...Middleware) *Router {
+	return addRoute(r, uri, handler, request.DeleteRequest, middlewares)
+}
+
+// Options creates an OPTIONS route.
+func (r *Router) Options(uri string, handler Handler, middlewares ...Middleware) *Router {
+	return addRoute(r, uri, handler, request.OptionsRequest, middlewares)
+}
+
+// Any creates a route that matches GET, HEAD, POST, PUT, PATCH, DELETE and OPTIONS.
+func (r *Router) Any(uri string, handler Handler, middlewares ...Middleware) *Router {
+	methods := []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions,
+	}
+	return addRoutes(r, methods, uri, handler, middlewares)
 }
 
-// Group certain routes uner certain options.
+// Match creates a route that matches the given HTTP methods, which
+// may be any method, like the WebDAV PROPFIND or MKCOL.
+func (r *Router) Match(methods []string, uri string, handler Handler, middlewares ...Middleware) *Router {
+	upper := make([]string, len(methods))
+	for i, method := range methods {
+		upper[i] = strings.ToUpper(method)
+	}
+	return addRoutes(r, upper, uri, handler, middlewares)
+}
+
+// Group certain routes uner certain options. The group inherits the
+// prefix, the middlewares and the domain (unless set) of the router.
 func (r *Router) Group(options *Options, routes func(r *Router)) *Router {
-	router := &Router{options: *options}
+	router := &Router{options: *options, parent: r, prefix: r.prefix + options.Prefix, domain: r.domain}
+	if options.Domain != "" {
+		router.domain = options.Domain
+	}
 	routes(router)
 	r.Childs = append(r.Childs, router)
 	return r
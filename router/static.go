@@ -0,0 +1,95 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+)
+
+// StaticOptions represents the static file route options.
+type StaticOptions struct {
+	// CacheControl is the Cache-Control header of the files, not set when empty.
+	CacheControl string
+	// Browse enables the directory listings.
+	Browse bool
+}
+
+// Static creates GET and HEAD routes serving the files of the directory
+// under the prefix, supporting Range and conditional requests.
+func (r *Router) Static(prefix string, dir string) *Router {
+	return r.StaticFS(prefix, http.Dir(dir), nil)
+}
+
+// StaticFS creates GET and HEAD routes serving the files of the file system
+// under the prefix. Any http.FileSystem can be used, like an embedded one.
+// The options may be nil.
+func (r *Router) StaticFS(prefix string, fs http.FileSystem, options *StaticOptions) *Router {
+	if options == nil {
+		options = &StaticOptions{}
+	}
+	prefix = strings.TrimSuffix(prefix, "/")
+	handler := staticHandler(r.prefix+prefix, fs, *options)
+	return r.Match([]string{http.MethodGet, http.MethodHead}, prefix+"/*filepath", handler)
+}
+
+// staticHandler serves the files of the file system.
+func staticHandler(prefix string, fs http.FileSystem, options StaticOptions) Handler {
+	browser := http.StripPrefix(prefix, http.FileServer(fs))
+	return func(req *request.HTTP) response.HTTP {
+		return response.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Cleaning a rooted path removes any ".." element.
+			name := path.Clean("/" + req.Params.ByName("filepath"))
+			file, err := fs.Open(name)
+			if err != nil {
+				staticError(w, err)
+				return
+			}
+			defer file.Close()
+			info, err := file.Stat()
+			if err != nil {
+				staticError(w, err)
+				return
+			}
+			if options.CacheControl != "" {
+				w.Header().Set("Cache-Control", options.CacheControl)
+			}
+			if info.IsDir() {
+				if options.Browse {
+					browser.ServeHTTP(w, r)
+					return
+				}
+				// Serve the directory index, if any.
+				index, err := fs.Open(path.Join(name, "index.html"))
+				if err != nil {
+					http.NotFound(w, r)
+					return
+				}
+				defer index.Close()
+				if info, err = index.Stat(); err != nil || info.IsDir() {
+					http.NotFound(w, r)
+					return
+				}
+				file = index
+			}
+			w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+			http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+		}))
+	}
+}
+
+// staticError responds with the status of the file system error.
+func staticError(w http.ResponseWriter, err error) {
+	switch {
+	case os.IsNotExist(err):
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	case os.IsPermission(err):
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	default:
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
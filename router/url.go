@@ -0,0 +1,84 @@
+package router
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+	"strings"
+
+	"github.com/pulsar-go/pulsar/response"
+)
+
+// init exposes the route URL generation to the views.
+func init() {
+	response.AddFuncs(template.FuncMap{"route": URL})
+}
+
+// Name names the routes added to the router by the last call.
+func (r *Router) Name(name string) *Router {
+	for i := r.last; i < len(r.Routes); i++ {
+		r.Routes[i].Name = name
+	}
+	return r
+}
+
+// Find returns the route with the given name from the router or its groups.
+func (r *Router) Find(name string) (*Route, bool) {
+	for i := range r.Routes {
+		if r.Routes[i].Name == name {
+			return &r.Routes[i], true
+		}
+	}
+	for _, child := range r.Childs {
+		if route, ok := child.Find(name); ok {
+			return route, true
+		}
+	}
+	return nil, false
+}
+
+// URL builds the URL of the named route filling its :param
+// and *catchall segments with the params in order.
+func (r *Router) URL(name string, params ...interface{}) (string, error) {
+	route, ok := r.Find(name)
+	if !ok {
+		return "", fmt.Errorf("Route '%s' is not defined", name)
+	}
+	return route.URL(params...)
+}
+
+// URL builds the URL of the route filling its :param
+// and *catchall segments with the params in order.
+func (route *Route) URL(params ...interface{}) (string, error) {
+	segments := strings.Split(route.URI, "/")
+	used := 0
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		if used >= len(params) {
+			return "", fmt.Errorf("Missing value for %s in route %s", segment, route.URI)
+		}
+		value := fmt.Sprint(params[used])
+		used++
+		if segment[0] == ':' {
+			segments[i] = url.PathEscape(value)
+			continue
+		}
+		// Catch all segments keep the slashes of the value.
+		parts := strings.Split(strings.TrimPrefix(value, "/"), "/")
+		for j, part := range parts {
+			parts[j] = url.PathEscape(part)
+		}
+		segments[i] = strings.Join(parts, "/")
+	}
+	if used != len(params) {
+		return "", fmt.Errorf("Too many values for route %s", route.URI)
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// URL builds the URL of the named global route.
+func URL(name string, params ...interface{}) (string, error) {
+	return Routes.URL(name, params...)
+}
@@ -0,0 +1,66 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+	"github.com/pulsar-go/pulsar/router"
+)
+
+// urlRoutes adds the named routes used by the URL tests.
+func urlRoutes(r *router.Router) {
+	r.Get("/", home).Name("home")
+	r.Get("/users/:user/photos/:photo", home).Name("users.photos.show")
+	r.Get("/files/*path", home).Name("files")
+	r.Group(&router.Options{Prefix: "/admin"}, func(r *router.Router) {
+		r.Get("/users/:user", home).Name("admin.users.show")
+	})
+}
+
+func TestURL(t *testing.T) {
+	r := &router.Router{}
+	urlRoutes(r)
+	tests := []struct {
+		name   string
+		params []interface{}
+		url    string
+		err    bool
+	}{
+		{"home", nil, "/", false},
+		{"users.photos.show", []interface{}{1, "summer"}, "/users/1/photos/summer", false},
+		{"users.photos.show", []interface{}{"a b", "x/y"}, "/users/a%20b/photos/x%2Fy", false},
+		{"files", []interface{}{"/docs/a b.pdf"}, "/files/docs/a%20b.pdf", false},
+		{"admin.users.show", []interface{}{uint(7)}, "/admin/users/7", false},
+		{"users.photos.show", []interface{}{1}, "", true},
+		{"home", []interface{}{1}, "", true},
+		{"missing", nil, "", true},
+	}
+	for _, tt := range tests {
+		url, err := r.URL(tt.name, tt.params...)
+		if (err != nil) != tt.err {
+			t.Errorf("URL(%q, %v) error = %v, want an error %v", tt.name, tt.params, err, tt.err)
+			continue
+		}
+		if url != tt.url {
+			t.Errorf("URL(%q, %v) = %q, want %q", tt.name, tt.params, url, tt.url)
+		}
+	}
+}
+
+func TestRequestURL(t *testing.T) {
+	w := serve(func(r *router.Router) {
+		urlRoutes(r)
+		r.Get("/link", func(req *request.HTTP) response.HTTP {
+			url, err := req.URL("admin.users.show", 3)
+			if err != nil {
+				return response.TextWithCode(err.Error(), http.StatusInternalServerError)
+			}
+			return response.Text(url)
+		})
+	}, http.MethodGet, "/link", nil)
+	if w.Code != http.StatusOK || w.Body.String() != "/admin/users/3" {
+		t.Errorf("GET /link = %d %q, want the URL of the application route", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,165 @@
+package session
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pulsar-go/pulsar/config"
+	"github.com/pulsar-go/pulsar/cookie"
+	"github.com/pulsar-go/pulsar/db"
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+	"github.com/pulsar-go/pulsar/router"
+)
+
+// ErrorNoSession is returned when the session middleware didn't run.
+var ErrorNoSession = errors.New("The request has no session, use the session middleware")
+
+// ErrorKey is returned when the app key can't encrypt the session cookie.
+var ErrorKey = errors.New("Sessions need an app key of at least 32 characters, set key in config/app.toml")
+
+// key stores the session in the request context.
+var key = request.NewKey("session")
+
+// Manager loads and saves the sessions of the requests.
+type Manager struct {
+	Store    Store
+	Cookie   string
+	Lifetime time.Duration
+	// id determines if the cookie stores the session id,
+	// instead of the session data.
+	id bool
+}
+
+// Default is the manager used by the Middleware function,
+// created from the settings on its first use.
+var Default *Manager
+
+// defaultOnce creates the default manager.
+var defaultOnce sync.Once
+
+// defaultError is the error creating the default manager.
+var defaultError error
+
+// NewManager creates a manager from the session settings and the app key
+// encrypting the session cookie, which must be at least 32 characters long.
+// The database store uses db.Builder when the database is nil.
+func NewManager(c *config.SessionConfig, key string, database *db.DB) (*Manager, error) {
+	if len(key) < 32 {
+		return nil, ErrorKey
+	}
+	lifetime, err := time.ParseDuration(c.Lifetime)
+	if err != nil {
+		return nil, err
+	}
+	var store Store
+	switch c.Driver {
+	case "memory":
+		store = NewMemoryStore()
+	case "file":
+		store = NewFileStore(c.Path)
+	case "database":
+		store = NewDatabaseStore(database)
+	case "cookie":
+		store = NewCookieStore()
+	default:
+		return nil, errors.New("Unknown session driver " + c.Driver)
+	}
+	return &Manager{Store: store, Cookie: c.Cookie, Lifetime: lifetime, id: c.Driver != "cookie"}, nil
+}
+
+// Middleware loads the session of the request with the default manager.
+// The routes respond with a 500 error when it can't be created.
+func Middleware(next router.Handler) router.Handler {
+	defaultOnce.Do(func() {
+		if Default == nil {
+			settings := config.Current()
+			Default, defaultError = NewManager(&settings.Session, settings.App.Key, nil)
+		}
+	})
+	if defaultError != nil {
+		log.Printf("[PULSAR] Unable to create the session manager: %s\n", defaultError)
+		return func(req *request.HTTP) response.HTTP {
+			return response.TextWithCode(http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+	}
+	return Default.Middleware(next)
+}
+
+// Middleware loads the session of the request before the
+// handler and saves it once the handler returns.
+func (m *Manager) Middleware(next router.Handler) router.Handler {
+	return func(req *request.HTTP) response.HTTP {
+		s := m.load(req)
+		req.Set(key, s)
+		return m.save(req, s, next(req))
+	}
+}
+
+// load returns the session of the request, or a new one when
+// the cookie is missing, modified or the session expired.
+func (m *Manager) load(req *request.HTTP) *Session {
+	if value, err := req.EncryptedCookie(m.Cookie); err == nil {
+		data, err := m.Store.Read(value)
+		if err != nil {
+			log.Printf("[PULSAR] Unable to read the session: %s\n", err)
+		} else if data != nil {
+			id := value
+			if !m.id {
+				id, _ = newID()
+			}
+			if s, err := decode(id, data); err == nil {
+				return s
+			}
+		}
+	}
+	s, err := newSession()
+	if err != nil {
+		log.Printf("[PULSAR] Unable to create the session: %s\n", err)
+		return &Session{values: make(map[string]interface{})}
+	}
+	return s
+}
+
+// save stores the session and queues its cookie in the response.
+func (m *Manager) save(req *request.HTTP, s *Session, res response.HTTP) response.HTTP {
+	s.mutex.Lock()
+	id, previousID, destroyed := s.id, s.previousID, s.destroyed
+	s.mutex.Unlock()
+	if previousID != "" {
+		if err := m.Store.Destroy(previousID); err != nil {
+			log.Printf("[PULSAR] Unable to destroy the session: %s\n", err)
+		}
+	}
+	if destroyed {
+		if err := m.Store.Destroy(id); err != nil {
+			log.Printf("[PULSAR] Unable to destroy the session: %s\n", err)
+		}
+		return res.WithoutCookie(m.Cookie)
+	}
+	data, err := s.encode()
+	if err != nil {
+		log.Printf("[PULSAR] Unable to encode the session: %s\n", err)
+		return res
+	}
+	value, err := m.Store.Write(id, data, m.Lifetime)
+	if err != nil {
+		log.Printf("[PULSAR] Unable to write the session: %s\n", err)
+		return res
+	}
+	c := cookie.New(m.Cookie, value)
+	c.MaxAge = int(m.Lifetime / time.Second)
+	return res.WithEncryptedCookie(c)
+}
+
+// Of returns the session of the request.
+func Of(req *request.HTTP) (*Session, error) {
+	value, ok := req.Get(key)
+	if !ok {
+		return nil, ErrorNoSession
+	}
+	return value.(*Session), nil
+}
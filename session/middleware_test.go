@@ -0,0 +1,68 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/pulsar-go/pulsar"
+	"github.com/pulsar-go/pulsar/config"
+	"github.com/pulsar-go/pulsar/request"
+	"github.com/pulsar-go/pulsar/response"
+	"github.com/pulsar-go/pulsar/router"
+)
+
+// count increments the visits stored in the session.
+func count(req *request.HTTP) response.HTTP {
+	s, err := Of(req)
+	if err != nil {
+		return response.TextWithCode(err.Error(), http.StatusInternalServerError)
+	}
+	visits, _ := s.GetInt("visits")
+	s.Put("visits", visits+1)
+	return response.Text(strconv.Itoa(visits + 1))
+}
+
+func TestMiddleware(t *testing.T) {
+	for _, driver := range []string{"memory", "cookie"} {
+		t.Run(driver, func(t *testing.T) {
+			c := config.Defaults()
+			c.App.Key = "0123456789abcdef0123456789abcdef"
+			c.Session.Driver = driver
+			m, err := NewManager(&c.Session, c.App.Key, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			app := pulsar.New(&c)
+			app.Router.Get("/", count, m.Middleware)
+			handler := app.Handler()
+			var cookies []*http.Cookie
+			for visit := 1; visit <= 3; visit++ {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				for _, cookie := range cookies {
+					r.AddCookie(cookie)
+				}
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, r)
+				if w.Body.String() != strconv.Itoa(visit) {
+					t.Fatalf("visit %d = %q, want the visits stored in the session", visit, w.Body.String())
+				}
+				if cookies = w.Result().Cookies(); len(cookies) != 1 || cookies[0].Name != c.Session.Cookie {
+					t.Fatalf("visit %d cookies = %v, want the session cookie", visit, cookies)
+				}
+			}
+		})
+	}
+}
+
+func TestMiddlewareWithoutKey(t *testing.T) {
+	c := config.Defaults()
+	app := pulsar.New(&c)
+	app.Router.Get("/", count, router.Middleware(Middleware))
+	w := httptest.NewRecorder()
+	app.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("GET / without an app key = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
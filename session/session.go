@@ -0,0 +1,195 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+)
+
+// Session stores the data of a client between requests.
+type Session struct {
+	id         string
+	previousID string
+	mutex      sync.Mutex
+	values     map[string]interface{}
+	// newFlash are the keys flashed in this request, available in the next one.
+	newFlash []string
+	// oldFlash are the keys flashed in the previous request, removed after this one.
+	oldFlash    []string
+	destroyed   bool
+	regenerated bool
+}
+
+// envelope is the stored representation of a session.
+type envelope struct {
+	Values map[string]interface{} `json:"values"`
+	Flash  []string               `json:"flash,omitempty"`
+}
+
+// newID generates a random session id.
+func newID() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(random), nil
+}
+
+// newSession creates an empty session with a new id.
+func newSession() (*Session, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	return &Session{id: id, values: make(map[string]interface{})}, nil
+}
+
+// decode creates the session with the id from its stored data. The
+// keys flashed by the previous request are removed after this one.
+func decode(id string, data []byte) (*Session, error) {
+	var e envelope
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	if e.Values == nil {
+		e.Values = make(map[string]interface{})
+	}
+	return &Session{id: id, values: e.Values, oldFlash: e.Flash}, nil
+}
+
+// encode returns the data to store, without the keys flashed by the previous request.
+func (s *Session) encode() ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, key := range s.oldFlash {
+		if !contains(s.newFlash, key) {
+			delete(s.values, key)
+		}
+	}
+	return json.Marshal(envelope{Values: s.values, Flash: s.newFlash})
+}
+
+// contains determines if the list contains the value.
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ID returns the session id.
+func (s *Session) ID() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.id
+}
+
+// Get returns a session value, or nil if it doesn't exist. Values are stored
+// as JSON, so numbers are read back as float64 and structs as maps.
+func (s *Session) Get(key string) interface{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.values[key]
+}
+
+// GetString returns a string session value.
+func (s *Session) GetString(key string) (string, bool) {
+	value, ok := s.Get(key).(string)
+	return value, ok
+}
+
+// GetInt returns an int session value.
+func (s *Session) GetInt(key string) (int, bool) {
+	switch value := s.Get(key).(type) {
+	case int:
+		return value, true
+	case float64:
+		return int(value), true
+	}
+	return 0, false
+}
+
+// GetBool returns a bool session value.
+func (s *Session) GetBool(key string) (bool, bool) {
+	value, ok := s.Get(key).(bool)
+	return value, ok
+}
+
+// Has determines if the session has the value.
+func (s *Session) Has(key string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.values[key]
+	return ok
+}
+
+// Put stores a session value.
+func (s *Session) Put(key string, value interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.values[key] = value
+}
+
+// Forget removes a session value.
+func (s *Session) Forget(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.values, key)
+}
+
+// Flash stores a session value that's only available until the end of the next request.
+func (s *Session) Flash(key string, value interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.values[key] = value
+	if !contains(s.newFlash, key) {
+		s.newFlash = append(s.newFlash, key)
+	}
+}
+
+// Reflash keeps the values flashed by the previous request for another request.
+func (s *Session) Reflash() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, key := range s.oldFlash {
+		if !contains(s.newFlash, key) {
+			s.newFlash = append(s.newFlash, key)
+		}
+	}
+}
+
+// Regenerate gives the session a new id keeping its values, which
+// should be done when the privileges change, like after a login.
+func (s *Session) Regenerate() error {
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.regenerated {
+		s.previousID = s.id
+	}
+	s.id = id
+	s.regenerated = true
+	return nil
+}
+
+// Invalidate removes all the session values and gives it a new id.
+func (s *Session) Invalidate() error {
+	s.mutex.Lock()
+	s.values = make(map[string]interface{})
+	s.newFlash, s.oldFlash = nil, nil
+	s.mutex.Unlock()
+	return s.Regenerate()
+}
+
+// Destroy removes the session from the store and the client.
+func (s *Session) Destroy() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.destroyed = true
+}
@@ -0,0 +1,132 @@
+package session
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pulsar-go/pulsar/config"
+)
+
+func TestStores(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pulsar-sessions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stores := []struct {
+		name  string
+		store Store
+	}{
+		{"memory", NewMemoryStore()},
+		{"file", NewFileStore(dir)},
+		{"cookie", NewCookieStore()},
+	}
+	for _, tt := range stores {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := newID()
+			if err != nil {
+				t.Fatal(err)
+			}
+			value, err := tt.store.Write(id, []byte(`{"values":{}}`), time.Hour)
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			data, err := tt.store.Read(value)
+			if err != nil || string(data) != `{"values":{}}` {
+				t.Errorf("Read() = %q, %v, want the written data", data, err)
+			}
+			expired, err := tt.store.Write(id, []byte(`{"values":{}}`), -time.Second)
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if data, err := tt.store.Read(expired); data != nil || err != nil {
+				t.Errorf("Read() of an expired session = %q, %v, want nil", data, err)
+			}
+		})
+	}
+}
+
+func TestFlash(t *testing.T) {
+	s, err := newSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Put("user", "pulsar")
+	s.Flash("status", "saved")
+	// Every step decodes the data saved by the previous request.
+	steps := []struct {
+		name   string
+		action func(*Session)
+		status bool
+	}{
+		{"next request", func(*Session) {}, true},
+		{"following request", func(*Session) {}, false},
+	}
+	for _, step := range steps {
+		data, err := s.encode()
+		if err != nil {
+			t.Fatalf("encode() error = %v", err)
+		}
+		if s, err = decode(s.ID(), data); err != nil {
+			t.Fatalf("decode() error = %v", err)
+		}
+		step.action(s)
+		if s.Has("status") != step.status {
+			t.Errorf("%s: Has(status) = %v, want %v", step.name, !step.status, step.status)
+		}
+		if value, ok := s.GetString("user"); !ok || value != "pulsar" {
+			t.Errorf("%s: GetString(user) = %q, %v, want the stored value", step.name, value, ok)
+		}
+	}
+}
+
+func TestRegenerate(t *testing.T) {
+	s, err := newSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := s.ID()
+	s.Put("user", "pulsar")
+	if err := s.Regenerate(); err != nil {
+		t.Fatal(err)
+	}
+	if s.ID() == id || s.previousID != id || !s.Has("user") {
+		t.Errorf("Regenerate() kept id %v, previous %q, values %v", s.ID() == id, s.previousID, s.values)
+	}
+	if err := s.Invalidate(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Has("user") || s.previousID != id {
+		t.Errorf("Invalidate() values = %v, previous %q", s.values, s.previousID)
+	}
+}
+
+func TestNewManager(t *testing.T) {
+	key := "0123456789abcdef0123456789abcdef"
+	tests := []struct {
+		name string
+		c    config.SessionConfig
+		key  string
+		err  bool
+	}{
+		{"memory", config.SessionConfig{Driver: "memory", Cookie: "session", Lifetime: "2h"}, key, false},
+		{"cookie", config.SessionConfig{Driver: "cookie", Cookie: "session", Lifetime: "2h"}, key, false},
+		{"no key", config.SessionConfig{Driver: "memory", Cookie: "session", Lifetime: "2h"}, "", true},
+		{"short key", config.SessionConfig{Driver: "memory", Cookie: "session", Lifetime: "2h"}, "secret", true},
+		{"unknown driver", config.SessionConfig{Driver: "redis", Cookie: "session", Lifetime: "2h"}, key, true},
+		{"invalid lifetime", config.SessionConfig{Driver: "memory", Cookie: "session", Lifetime: "2"}, key, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewManager(&tt.c, tt.key, nil)
+			if (err != nil) != tt.err {
+				t.Fatalf("NewManager() error = %v, want an error %v", err, tt.err)
+			}
+			if err == nil && m.id != (tt.c.Driver != "cookie") {
+				t.Errorf("NewManager() id = %v, want the cookie to store the id for %s", m.id, tt.c.Driver)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package session
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pulsar-go/pulsar/db"
+)
+
+// Store stores the session data.
+type Store interface {
+	// Read returns the data of the session, or nil if it
+	// doesn't exist or expired.
+	Read(id string) ([]byte, error)
+	// Write stores the data of the session for the lifetime and
+	// returns the value of the session cookie.
+	Write(id string, data []byte, lifetime time.Duration) (string, error)
+	// Destroy removes the session.
+	Destroy(id string) error
+}
+
+// validID matches the session ids generated by pulsar.
+var validID = regexp.MustCompile("^[0-9a-f]{64}$")
+
+// memoryEntry is a session stored in memory.
+type memoryEntry struct {
+	data    []byte
+	expires time.Time
+}
+
+// MemoryStore stores the sessions in memory, so they're lost on restart
+// and not shared between instances.
+type MemoryStore struct {
+	mutex    sync.Mutex
+	sessions map[string]memoryEntry
+	writes   int
+}
+
+// NewMemoryStore creates a memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: make(map[string]memoryEntry)}
+}
+
+// Read returns the data of the session.
+func (m *MemoryStore) Read(id string) ([]byte, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	entry, ok := m.sessions[id]
+	if !ok || time.Now().After(entry.expires) {
+		delete(m.sessions, id)
+		return nil, nil
+	}
+	return entry.data, nil
+}
+
+// Write stores the data of the session.
+func (m *MemoryStore) Write(id string, data []byte, lifetime time.Duration) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.sessions[id] = memoryEntry{data: data, expires: time.Now().Add(lifetime)}
+	// Remove the expired sessions from time to time.
+	if m.writes++; m.writes%100 == 0 {
+		now := time.Now()
+		for key, entry := range m.sessions {
+			if now.After(entry.expires) {
+				delete(m.sessions, key)
+			}
+		}
+	}
+	return id, nil
+}
+
+// Destroy removes the session.
+func (m *MemoryStore) Destroy(id string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
+
+// FileStore stores every session in a file of the directory.
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore creates a file store in the directory.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{Dir: dir}
+}
+
+// path returns the file of the session.
+func (f *FileStore) path(id string) string {
+	return filepath.Join(f.Dir, id)
+}
+
+// Read returns the data of the session.
+func (f *FileStore) Read(id string) ([]byte, error) {
+	if !validID.MatchString(id) {
+		return nil, nil
+	}
+	info, err := os.Stat(f.path(id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The modification time is set to the expiration time.
+	if time.Now().After(info.ModTime()) {
+		os.Remove(f.path(id))
+		return nil, nil
+	}
+	return ioutil.ReadFile(f.path(id))
+}
+
+// Write stores the data of the session.
+func (f *FileStore) Write(id string, data []byte, lifetime time.Duration) (string, error) {
+	if err := os.MkdirAll(f.Dir, 0700); err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(f.path(id), data, 0600); err != nil {
+		return "", err
+	}
+	expires := time.Now().Add(lifetime)
+	return id, os.Chtimes(f.path(id), expires, expires)
+}
+
+// Destroy removes the session.
+func (f *FileStore) Destroy(id string) error {
+	if !validID.MatchString(id) {
+		return nil
+	}
+	if err := os.Remove(f.path(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Record is the database model of a session.
+type Record struct {
+	ID        string `gorm:"primary_key;size:64"`
+	Data      []byte
+	ExpiresAt time.Time `sql:"index"`
+}
+
+// TableName returns the table of the sessions.
+func (Record) TableName() string {
+	return "sessions"
+}
+
+// DatabaseStore stores the sessions in the sessions table of the database.
+type DatabaseStore struct {
+	// DB is the database used, db.Builder when nil.
+	DB       *db.DB
+	migrated sync.Once
+}
+
+// NewDatabaseStore creates a database store, the database may be nil.
+func NewDatabaseStore(database *db.DB) *DatabaseStore {
+	return &DatabaseStore{DB: database}
+}
+
+// database returns the database, creating the sessions table if needed.
+func (d *DatabaseStore) database() *db.DB {
+	database := d.DB
+	if database == nil {
+		database = db.Builder
+	}
+	d.migrated.Do(func() {
+		database.AutoMigrate(&Record{})
+	})
+	return database
+}
+
+// Read returns the data of the session.
+func (d *DatabaseStore) Read(id string) ([]byte, error) {
+	var record Record
+	result := d.database().Where("id", id).First(&record)
+	if result.RecordNotFound() {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if time.Now().After(record.ExpiresAt) {
+		return nil, d.Destroy(id)
+	}
+	return record.Data, nil
+}
+
+// Write stores the data of the session.
+func (d *DatabaseStore) Write(id string, data []byte, lifetime time.Duration) (string, error) {
+	record := Record{ID: id, Data: data, ExpiresAt: time.Now().Add(lifetime)}
+	return id, d.database().Save(&record).Error
+}
+
+// Destroy removes the session.
+func (d *DatabaseStore) Destroy(id string) error {
+	return d.database().Where("id", id).Delete(&Record{}).Error
+}
+
+// CookieStore stores the session data in the encrypted session cookie itself,
+// so it must stay small, since browsers limit cookies to about 4KB. The data
+// carries its expiration time, but a copy of the cookie stays valid until
+// then, since destroying the session only removes the cookie from the client.
+type CookieStore struct{}
+
+// NewCookieStore creates a cookie store.
+func NewCookieStore() *CookieStore {
+	return &CookieStore{}
+}
+
+// Read returns the session data from the cookie value, or nil if it expired.
+func (CookieStore) Read(value string) ([]byte, error) {
+	parts := strings.SplitN(value, "|", 2)
+	if len(parts) != 2 {
+		return nil, nil
+	}
+	expires, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || time.Now().Unix() >= expires {
+		return nil, nil
+	}
+	return []byte(parts[1]), nil
+}
+
+// Write returns the session data and its expiration time as the cookie value.
+func (CookieStore) Write(id string, data []byte, lifetime time.Duration) (string, error) {
+	return strconv.FormatInt(time.Now().Add(lifetime).Unix(), 10) + "|" + string(data), nil
+}
+
+// Destroy does nothing, since the cookie is removed from the client.
+func (CookieStore) Destroy(id string) error {
+	return nil
+}